@@ -12,16 +12,28 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rikeda71/go-gql-sqlc-template/db/migrations"
 	"github.com/rikeda71/go-gql-sqlc-template/internal"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/event"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/health"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/requestid"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 const (
 	LogCountTotal = "log_count"
-	level         = "level"
 )
 
+// logLabels is labels of LogCountTotal
+type logLabels struct {
+	Level string `label:"level"`
+}
+
 func main() {
 	cnf, err := internal.NewConfig()
 	if err != nil {
@@ -30,7 +42,7 @@ func main() {
 
 	// metrics
 	m := metrics.NewClient()
-	m.RegisterCounter(LogCountTotal, "ログの出現回数", level)
+	logCount := metrics.NewCounter[logLabels](m, LogCountTotal, "ログの出現回数")
 
 	// logger
 	logLevel := slog.LevelInfo
@@ -44,12 +56,27 @@ func main() {
 			// count by log level
 			switch {
 			case a.Key == slog.LevelKey:
-				m.Count(LogCountTotal, 1, a.Value.String())
+				logCount.Inc(logLabels{Level: a.Value.String()})
 			}
 			return a
 		},
 	}
-	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, opt)))
+	/// logs are correlated with requests by the request ID in the context
+	slog.SetDefault(slog.New(requestid.NewLogHandler(slog.NewJSONHandler(os.Stderr, opt))))
+
+	// tracing
+	tp, err := tracing.NewTracerProvider(context.Background(), cnf.TracingConfig())
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		// flush buffered spans
+		if err := tp.Shutdown(context.Background()); err != nil {
+			slog.Error("failed to shutdown tracer provider", "error", err.Error())
+		}
+	}()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 
 	// infrastructure
 	/// db
@@ -58,6 +85,7 @@ func main() {
 		slog.Error("failed to connect db", "error", err.Error())
 		panic(err)
 	}
+	poolCnf.ConnConfig.Tracer = tracing.NewQueryTracer(tp)
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolCnf)
 	if err != nil {
 		slog.Error("failed to connect db", "error", err.Error())
@@ -66,16 +94,39 @@ func main() {
 	defer func() {
 		pool.Close()
 	}()
-	q := db.New(pool)
+	/// SQL is tagged with the request ID if enabled, which disables the statement cache
+	var dbtx db.DBTX = pool
+	if cnf.SQLRequestIDComment {
+		dbtx = requestid.NewDBTX(pool)
+	}
+	q := db.New(dbtx)
+	/// events
+	//// the broker must stop before the pool is closed because it holds a connection
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	defer stopBroker()
+	broker := event.NewBroker(pool, q)
+	go broker.Run(brokerCtx)
 
 	// presentation
-	gqlHandler, err := internal.NewGraphQLHandler(cnf, q, m)
+	verifier, err := auth.NewVerifier(cnf.VerifierConfig())
+	if err != nil {
+		panic(err)
+	}
+	gqlHandler, err := internal.NewGraphQLHandler(cnf, q, broker, verifier, m)
+	if err != nil {
+		panic(err)
+	}
+	migrationVersion, err := migrations.LatestVersion()
 	if err != nil {
 		panic(err)
 	}
-	s := internal.NewServer(cnf.Port, *gqlHandler)
+	healthHandler := health.NewHandler(
+		health.NewPingChecker(pool),
+		health.NewMigrationChecker(q, migrationVersion),
+	)
+	s := internal.NewServer(cnf.Port, *gqlHandler, healthHandler, verifier, m)
 	go func() {
-		if err := s.Start(cnf.DebugMode); !errors.Is(err, http.ErrServerClosed) {
+		if err := s.Start(cnf.PlaygroundConfig()); !errors.Is(err, http.ErrServerClosed) {
 			slog.Error("could not start server.", "err", err.Error())
 		}
 	}()
@@ -95,7 +146,7 @@ func main() {
 
 		slog.Info("shutting down server with graceful...")
 		defer wg.Done()
-		if err := server.Shutdown(ctx); err != nil {
+		if err := server.Shutdown(ctx, cnf.ShutdownDrainDelay); err != nil {
 			slog.Error("graceful shutdown failed.", "err", err.Error())
 		}
 	}(*s)
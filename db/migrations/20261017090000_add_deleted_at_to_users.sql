@@ -0,0 +1,6 @@
+-- migrate:up
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+COMMENT ON COLUMN users.deleted_at IS 'Deletion Date';
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN deleted_at;
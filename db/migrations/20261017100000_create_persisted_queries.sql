@@ -0,0 +1,13 @@
+-- migrate:up
+CREATE TABLE persisted_queries (
+    hash CHAR(64) PRIMARY KEY,
+    query TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+COMMENT ON TABLE persisted_queries IS 'Automatic Persisted Queries';
+COMMENT ON COLUMN persisted_queries.hash IS 'SHA-256 Hash of Query';
+COMMENT ON COLUMN persisted_queries.query IS 'Query Document';
+COMMENT ON COLUMN persisted_queries.created_at IS 'Creation Date';
+
+-- migrate:down
+DROP TABLE persisted_queries;
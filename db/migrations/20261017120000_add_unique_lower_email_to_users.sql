@@ -0,0 +1,7 @@
+-- migrate:up
+-- email addresses are unique case-insensitively, including rows written before they were lowercased
+-- this fails if addresses differ only in case, which must be merged before the migration
+CREATE UNIQUE INDEX users_email_lower_key ON users (lower(email));
+
+-- migrate:down
+DROP INDEX users_email_lower_key;
@@ -0,0 +1,27 @@
+// Package migrations embeds migration files of dbmate
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// LatestVersion returns the version of the latest migration file
+// (ex. "20240723050456" of "20240723050456_initialize_users.sql")
+func LatestVersion() (string, error) {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	version, _, _ := strings.Cut(names[len(names)-1], "_")
+	return version, nil
+}
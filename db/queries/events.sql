@@ -0,0 +1,4 @@
+-- name: NotifyEvent :exec
+-- listeners on every replica receive the payload
+SELECT /* events_001 */
+    pg_notify(sqlc.arg('channel')::text, sqlc.arg('payload')::text);
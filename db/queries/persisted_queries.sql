@@ -0,0 +1,14 @@
+-- name: FindPersistedQuery :one
+SELECT /* persisted_queries_001 */
+    query
+FROM persisted_queries
+WHERE hash = $1;
+
+-- name: InsertPersistedQuery :exec
+-- the same hash always has the same query, so conflicts are ignored
+-- queries are not inserted when the table already has max_rows rows
+INSERT INTO persisted_queries /* persisted_queries_002 */
+(hash, query)
+SELECT sqlc.arg(hash), sqlc.arg(query)
+WHERE (SELECT count(*) FROM persisted_queries) < sqlc.arg(max_rows)::BIGINT
+ON CONFLICT (hash) DO NOTHING;
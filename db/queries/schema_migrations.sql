@@ -0,0 +1,6 @@
+-- name: FindLatestMigrationVersion :one
+SELECT /* schema_migrations_001 */
+    version
+FROM schema_migrations
+ORDER BY version DESC
+LIMIT 1;
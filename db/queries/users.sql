@@ -4,7 +4,71 @@ INSERT INTO users /* users_001 */
 RETURNING *;
 
 -- name: FindUserByID :one
+-- soft-deleted users are excluded unless include_deleted is true
 SELECT /* users_002 */
     *
 FROM users
-WHERE id = $1;
+WHERE id = sqlc.arg('id')
+    AND (deleted_at IS NULL OR sqlc.arg('include_deleted')::boolean);
+
+-- name: UpdateUser :one
+UPDATE users /* users_003 */
+SET
+    user_name = COALESCE(sqlc.narg('user_name'), user_name),
+    email = COALESCE(sqlc.narg('email'), email),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+    AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteUser :one
+UPDATE users /* users_004 */
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+    AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreUser :one
+UPDATE users /* users_005 */
+SET
+    deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+    AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListUsersAscending :many
+-- keyset pagination on id (UUIDv7 is time-ordered)
+-- missing bounds are replaced with an empty string and 'g', which enclose all lowercase UUIDs
+-- so both bounds stay index range conditions in generic plans of prepared statements
+SELECT /* users_006 */
+    *
+FROM users
+WHERE id > COALESCE(sqlc.narg('lower_id')::bpchar, '')
+    AND id < COALESCE(sqlc.narg('upper_id')::bpchar, 'g')
+    AND (deleted_at IS NULL OR sqlc.arg('include_deleted')::boolean)
+ORDER BY id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: ListUsersDescending :many
+-- keyset pagination on id (UUIDv7 is time-ordered)
+-- missing bounds are replaced with an empty string and 'g', which enclose all lowercase UUIDs
+-- so both bounds stay index range conditions in generic plans of prepared statements
+SELECT /* users_007 */
+    *
+FROM users
+WHERE id > COALESCE(sqlc.narg('lower_id')::bpchar, '')
+    AND id < COALESCE(sqlc.narg('upper_id')::bpchar, 'g')
+    AND (deleted_at IS NULL OR sqlc.arg('include_deleted')::boolean)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: FindUsersByIDs :many
+-- soft-deleted users are excluded
+SELECT /* users_008 */
+    *
+FROM users
+WHERE id = ANY(sqlc.arg('ids')::bpchar[])
+    AND deleted_at IS NULL;
@@ -13,6 +13,45 @@ SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: persisted_queries; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.persisted_queries (
+    hash character(64) NOT NULL,
+    query text NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
+--
+-- Name: TABLE persisted_queries; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.persisted_queries IS 'Automatic Persisted Queries';
+
+
+--
+-- Name: COLUMN persisted_queries.hash; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.persisted_queries.hash IS 'SHA-256 Hash of Query';
+
+
+--
+-- Name: COLUMN persisted_queries.query; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.persisted_queries.query IS 'Query Document';
+
+
+--
+-- Name: COLUMN persisted_queries.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.persisted_queries.created_at IS 'Creation Date';
+
+
 --
 -- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
 --
@@ -30,8 +69,9 @@ CREATE TABLE public.users (
     id character(36) NOT NULL,
     user_name character varying(50) NOT NULL,
     email character varying(100) NOT NULL,
-    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
 );
 
 
@@ -77,6 +117,21 @@ COMMENT ON COLUMN public.users.created_at IS 'Creation Date';
 COMMENT ON COLUMN public.users.updated_at IS 'Last Update Date';
 
 
+--
+-- Name: COLUMN users.deleted_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.users.deleted_at IS 'Deletion Date';
+
+
+--
+-- Name: persisted_queries persisted_queries_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.persisted_queries
+    ADD CONSTRAINT persisted_queries_pkey PRIMARY KEY (hash);
+
+
 --
 -- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -109,6 +164,13 @@ ALTER TABLE ONLY public.users
     ADD CONSTRAINT users_user_name_key UNIQUE (user_name);
 
 
+--
+-- Name: users_email_lower_key; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX users_email_lower_key ON public.users USING btree (lower((email)::text));
+
+
 --
 -- PostgreSQL database dump complete
 --
@@ -119,4 +181,8 @@ ALTER TABLE ONLY public.users
 --
 
 INSERT INTO public.schema_migrations (version) VALUES
-    ('20240723050456');
+    ('20240723050456'),
+    ('20261017090000'),
+    ('20261017100000'),
+    ('20261017110000'),
+    ('20261017120000');
@@ -0,0 +1,128 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Code is a machine-readable classification of an error
+type Code string
+
+const (
+	// CodeAlreadyExists is returned when a unique constraint is violated
+	CodeAlreadyExists Code = "ALREADY_EXISTS"
+	// CodeNotFound is returned when the target does not exist
+	CodeNotFound Code = "NOT_FOUND"
+	// CodeValidation is returned when an input value is invalid
+	CodeValidation Code = "VALIDATION_ERROR"
+	// CodeUnauthenticated is returned when the request is not authenticated
+	CodeUnauthenticated Code = "UNAUTHENTICATED"
+	// CodeForbidden is returned when the principal is not allowed to do the operation
+	CodeForbidden Code = "FORBIDDEN"
+	// CodeInternal is returned when the error can not be classified
+	CodeInternal Code = "INTERNAL"
+)
+
+// PostgreSQL error codes
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation    = "23505"
+	pgCheckViolation     = "23514"
+	pgNotNullViolation   = "23502"
+	pgStringDataTooLong  = "22001"
+	pgInvalidTextFormat  = "22P02"
+	pgNumericOutOfRange  = "22003"
+	pgInvalidDatetimeFmt = "22007"
+)
+
+// Error is an error with a code and the input field which caused it
+type Error struct {
+	// Code is a classification of the error
+	Code Code
+	// Field is a path of the input field which caused the error (ex. "input.email")
+	// empty if the error is not related to a specific field
+	Field string
+	// Message is a human-readable message which can be shown to clients
+	Message string
+	// err is the original error
+	err error
+}
+
+// New is a constructor for Error
+func New(code Code, field string, message string) *Error {
+	return &Error{Code: code, Field: field, Message: message}
+}
+
+// Wrap is a constructor for Error which keeps the original error
+func Wrap(err error, code Code, field string, message string) *Error {
+	return &Error{Code: code, Field: field, Message: message, err: err}
+}
+
+func (e *Error) Error() string {
+	if e.err == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// As finds the first Error in err's chain
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// CodeOf returns the code of err, or CodeInternal if err is not an Error
+func CodeOf(err error) Code {
+	if e, ok := As(err); ok {
+		return e.Code
+	}
+	return CodeInternal
+}
+
+// FromDB classifies an error returned by pgx
+// constraintFields maps constraint names (ex. "users_email_key") to input field paths (ex. "input.email")
+// errors which can not be classified are wrapped with CodeInternal
+func FromDB(err error, constraintFields map[string]string) *Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := As(err); ok {
+		return e
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Wrap(err, CodeNotFound, "", "not found")
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return Wrap(err, CodeInternal, "", "internal error")
+	}
+
+	field := constraintFields[pgErr.ConstraintName]
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		if field == "" {
+			return Wrap(err, CodeAlreadyExists, "", "already exists")
+		}
+		return Wrap(err, CodeAlreadyExists, field, fmt.Sprintf("%s already exists", field))
+	case pgCheckViolation, pgNotNullViolation:
+		return Wrap(err, CodeValidation, field, "invalid value")
+	case pgStringDataTooLong:
+		// PostgreSQL does not report which column is too long
+		return Wrap(err, CodeValidation, field, "value too long")
+	case pgInvalidTextFormat, pgNumericOutOfRange, pgInvalidDatetimeFmt:
+		return Wrap(err, CodeValidation, field, "invalid format")
+	default:
+		return Wrap(err, CodeInternal, "", "internal error")
+	}
+}
@@ -0,0 +1,80 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestFromDB(t *testing.T) {
+	constraintFields := map[string]string{
+		"users_user_name_key": "input.name",
+		"users_email_key":     "input.email",
+	}
+
+	type expected struct {
+		Code  Code
+		Field string
+	}
+
+	testCases := map[string]struct {
+		err  error
+		want expected
+	}{
+		"success: unique_violation_email": {
+			err:  &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"},
+			want: expected{Code: CodeAlreadyExists, Field: "input.email"},
+		},
+		"success: unique_violation_user_name": {
+			err:  fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "23505", ConstraintName: "users_user_name_key"}),
+			want: expected{Code: CodeAlreadyExists, Field: "input.name"},
+		},
+		"success: unique_violation_unknown_constraint": {
+			err:  &pgconn.PgError{Code: "23505", ConstraintName: "users_pkey"},
+			want: expected{Code: CodeAlreadyExists, Field: ""},
+		},
+		"success: check_violation": {
+			err:  &pgconn.PgError{Code: "23514", ConstraintName: "users_email_key"},
+			want: expected{Code: CodeValidation, Field: "input.email"},
+		},
+		"success: string_data_too_long": {
+			err:  &pgconn.PgError{Code: "22001"},
+			want: expected{Code: CodeValidation, Field: ""},
+		},
+		"success: unknown_pg_error": {
+			err:  &pgconn.PgError{Code: "40001"},
+			want: expected{Code: CodeInternal, Field: ""},
+		},
+		"success: no_rows": {
+			err:  fmt.Errorf("wrapped: %w", pgx.ErrNoRows),
+			want: expected{Code: CodeNotFound, Field: ""},
+		},
+		"success: not_pg_error": {
+			err:  errors.New("connection refused"),
+			want: expected{Code: CodeInternal, Field: ""},
+		},
+		"success: already_classified": {
+			err:  New(CodeValidation, "input.name", "name must not be empty"),
+			want: expected{Code: CodeValidation, Field: "input.name"},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got := FromDB(tt.err, constraintFields)
+			if diff := cmp.Diff(tt.want, expected{Code: got.Code, Field: got.Field}); diff != "" {
+				t.Errorf("unexpected error: %v", diff)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("original error is not wrapped: %v", got)
+			}
+		})
+	}
+}
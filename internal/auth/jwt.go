@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned when a token can not be verified
+var ErrInvalidToken = errors.New("invalid token")
+
+// VerifierConfig is the configuration of Verifier
+// keys which are not set are not accepted
+type VerifierConfig struct {
+	// HMACSecret is a shared secret of HS256
+	HMACSecret []byte
+	// RSAPublicKeyPEM is a PEM encoded public key of RS256
+	RSAPublicKeyPEM []byte
+	// JWKSFile is a path of a local JWKS file which contains public keys of RS256
+	JWKSFile string
+	// Issuer is the expected `iss` claim, not checked if empty
+	Issuer string
+	// Audience is the expected `aud` claim, not checked if empty
+	Audience string
+}
+
+// Claims is the claims of an access token
+type Claims struct {
+	jwt.RegisteredClaims
+	// Role is a role of the user, RoleUser if empty
+	Role Role `json:"role,omitempty"`
+}
+
+// Verifier verifies JWTs and converts them into principals
+type Verifier struct {
+	hmacSecret []byte
+	// rsaKeys is public keys of RS256
+	// key: kid ("" for the key without kid), value: public key
+	rsaKeys map[string]*rsa.PublicKey
+	parser  *jwt.Parser
+}
+
+// NewVerifier is a constructor for Verifier
+func NewVerifier(cnf VerifierConfig) (*Verifier, error) {
+	v := &Verifier{
+		hmacSecret: cnf.HMACSecret,
+		rsaKeys:    make(map[string]*rsa.PublicKey),
+	}
+
+	methods := make([]string, 0, 2)
+	if len(cnf.HMACSecret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(cnf.RSAPublicKeyPEM) > 0 {
+		key, err := jwt.ParseRSAPublicKeyFromPEM(cnf.RSAPublicKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rsa public key: %w", err)
+		}
+		v.rsaKeys[""] = key
+	}
+	if cnf.JWKSFile != "" {
+		keys, err := loadJWKSFile(cnf.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		for kid, key := range keys {
+			v.rsaKeys[kid] = key
+		}
+	}
+	if len(v.rsaKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cnf.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cnf.Issuer))
+	}
+	if cnf.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cnf.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// Verify verifies a token and returns its principal
+func (v *Verifier) Verify(tokenString string) (*Principal, error) {
+	var claims Claims
+	if _, err := v.parser.ParseWithClaims(tokenString, &claims, v.key); err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.Join(ErrInvalidToken, errors.New("sub claim is required"))
+	}
+
+	role := claims.Role
+	switch role {
+	case "":
+		role = RoleUser
+	case RoleUser, RoleAdmin:
+	default:
+		return nil, errors.Join(ErrInvalidToken, fmt.Errorf("unknown role: %s", role))
+	}
+	return &Principal{UserID: claims.Subject, Role: role}, nil
+}
+
+// key returns the key to verify the token
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		// an empty secret must not be used, otherwise anyone can sign tokens
+		if len(v.hmacSecret) == 0 {
+			return nil, errors.New("hmac secret is not configured")
+		}
+		return v.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.rsaKeys[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+	}
+}
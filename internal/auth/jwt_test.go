@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+var hmacSecret = []byte("test-secret")
+
+func newClaims(sub string, role Role, expiresIn time.Duration) Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   sub,
+			Issuer:    "test-issuer",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+		Role: role,
+	}
+}
+
+func signHS256(t *testing.T, claims Claims, secret []byte) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return s
+}
+
+func signRS256(t *testing.T, claims Claims, key *rsa.PrivateKey, kid string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return s
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	return key
+}
+
+func writeJWKSFile(t *testing.T, kid string, key *rsa.PublicKey) string {
+	t.Helper()
+	set := jwks{Keys: []jwk{{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to marshal jwks: %v", err)
+	}
+	p := path.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(p, b, 0o600); err != nil {
+		t.Fatalf("failed to write jwks: %v", err)
+	}
+	return p
+}
+
+func TestVerify(t *testing.T) {
+	jwksKey := generateRSAKey(t)
+	pemKey := generateRSAKey(t)
+	otherKey := generateRSAKey(t)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&pemKey.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	v, err := NewVerifier(VerifierConfig{
+		HMACSecret:      hmacSecret,
+		RSAPublicKeyPEM: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		JWKSFile:        writeJWKSFile(t, "key-1", &jwksKey.PublicKey),
+		Issuer:          "test-issuer",
+	})
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	testCases := map[string]struct {
+		token   string
+		want    *Principal
+		wantErr bool
+	}{
+		"success: hs256_default_role": {
+			token: signHS256(t, newClaims("user-1", "", time.Hour), hmacSecret),
+			want:  &Principal{UserID: "user-1", Role: RoleUser},
+		},
+		"success: hs256_admin": {
+			token: signHS256(t, newClaims("user-2", RoleAdmin, time.Hour), hmacSecret),
+			want:  &Principal{UserID: "user-2", Role: RoleAdmin},
+		},
+		"success: rs256_jwks": {
+			token: signRS256(t, newClaims("user-3", RoleUser, time.Hour), jwksKey, "key-1"),
+			want:  &Principal{UserID: "user-3", Role: RoleUser},
+		},
+		"success: rs256_pem": {
+			token: signRS256(t, newClaims("user-4", RoleUser, time.Hour), pemKey, ""),
+			want:  &Principal{UserID: "user-4", Role: RoleUser},
+		},
+		"failure: expired": {
+			token:   signHS256(t, newClaims("user-1", "", -time.Hour), hmacSecret),
+			wantErr: true,
+		},
+		"failure: wrong_secret": {
+			token:   signHS256(t, newClaims("user-1", "", time.Hour), []byte("wrong")),
+			wantErr: true,
+		},
+		"failure: unknown_kid": {
+			token:   signRS256(t, newClaims("user-1", "", time.Hour), jwksKey, "key-2"),
+			wantErr: true,
+		},
+		"failure: wrong_rsa_key": {
+			token:   signRS256(t, newClaims("user-1", "", time.Hour), otherKey, "key-1"),
+			wantErr: true,
+		},
+		"failure: wrong_issuer": {
+			token: signHS256(t, Claims{RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user-1",
+				Issuer:    "other-issuer",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			}}, hmacSecret),
+			wantErr: true,
+		},
+		"failure: unknown_role": {
+			token:   signHS256(t, newClaims("user-1", "ROOT", time.Hour), hmacSecret),
+			wantErr: true,
+		},
+		"failure: no_subject": {
+			token:   signHS256(t, newClaims("", "", time.Hour), hmacSecret),
+			wantErr: true,
+		},
+		"failure: malformed": {
+			token:   "not.a.token",
+			wantErr: true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := v.Verify(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected principal: %v", diff)
+			}
+		})
+	}
+}
+
+func TestVerifyWithoutKeys(t *testing.T) {
+	v, err := NewVerifier(VerifierConfig{})
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	// a token signed with an empty secret must not be accepted
+	token := signHS256(t, newClaims("user-1", RoleAdmin, time.Hour), []byte{})
+	if _, err := v.Verify(token); err == nil {
+		t.Errorf("token should be rejected")
+	}
+}
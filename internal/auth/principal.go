@@ -0,0 +1,39 @@
+package auth
+
+import "context"
+
+// Role is a role of an authenticated principal
+type Role string
+
+const (
+	// RoleUser is a role of an ordinary user
+	RoleUser Role = "USER"
+	// RoleAdmin is a role of an administrator
+	RoleAdmin Role = "ADMIN"
+)
+
+// Principal is an authenticated subject of a request
+type Principal struct {
+	// UserID is an ID of the authenticated user
+	UserID string
+	// Role is a role of the authenticated user
+	Role Role
+}
+
+// IsAdmin reports whether the principal is an administrator
+func (p *Principal) IsAdmin() bool {
+	return p != nil && p.Role == RoleAdmin
+}
+
+type principalKey struct{}
+
+// NewContext returns a new context which carries the principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, or nil if the request is not authenticated
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
@@ -0,0 +1,130 @@
+package cachecontrol
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const directiveName = "cacheControl"
+
+type ctxKey struct{}
+
+// Policy is the cache policy of a response
+// it is restricted by every resolved field, so the smallest maxAge wins
+type Policy struct {
+	mu     sync.Mutex
+	maxAge *int
+}
+
+// Restrict lowers maxAge of the response
+func (p *Policy) Restrict(maxAge int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.maxAge == nil || maxAge < *p.maxAge {
+		p.maxAge = &maxAge
+	}
+}
+
+// MaxAge returns maxAge of the response, 0 if no field is resolved
+func (p *Policy) MaxAge() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.maxAge == nil {
+		return 0
+	}
+	return *p.maxAge
+}
+
+// Header returns the value of Cache-Control header
+// responses for authenticated users are cached only by browsers
+func (p *Policy) Header(private bool) string {
+	maxAge := p.MaxAge()
+	switch {
+	case maxAge <= 0:
+		return "no-store"
+	case private:
+		return fmt.Sprintf("private, max-age=%d", maxAge)
+	default:
+		return fmt.Sprintf("public, max-age=%d", maxAge)
+	}
+}
+
+// NewContext returns a context with the policy
+func NewContext(ctx context.Context, p *Policy) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the policy, nil if the response is not cacheable
+func FromContext(ctx context.Context) *Policy {
+	p, _ := ctx.Value(ctxKey{}).(*Policy)
+	return p
+}
+
+// Extension is a gqlgen extension restricting the policy by @cacheControl of resolved fields
+type Extension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+	graphql.ResponseInterceptor
+} = Extension{}
+
+func (Extension) ExtensionName() string {
+	return "CacheControl"
+}
+
+func (Extension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Extension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	p := FromContext(ctx)
+	if p == nil {
+		return next(ctx)
+	}
+
+	fc := graphql.GetFieldContext(ctx)
+	maxAge, ok := fieldMaxAge(fc.Field.Definition)
+	switch {
+	case ok:
+		p.Restrict(maxAge)
+	case fc.Parent == nil:
+		// root fields must opt in to caching
+		p.Restrict(0)
+	}
+	return next(ctx)
+}
+
+func (Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if p := FromContext(ctx); p != nil && resp != nil && len(resp.Errors) > 0 {
+		// errors may be temporary, so they must not be cached
+		p.Restrict(0)
+	}
+	return resp
+}
+
+// fieldMaxAge returns maxAge of @cacheControl on the field
+func fieldMaxAge(def *ast.FieldDefinition) (int, bool) {
+	if def == nil {
+		return 0, false
+	}
+	d := def.Directives.ForName(directiveName)
+	if d == nil {
+		return 0, false
+	}
+	arg := d.Arguments.ForName("maxAge")
+	if arg == nil {
+		return 0, false
+	}
+	maxAge, err := strconv.Atoi(arg.Value.Raw)
+	if err != nil {
+		return 0, false
+	}
+	return maxAge, true
+}
@@ -0,0 +1,191 @@
+package cachecontrol
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestInterceptField(t *testing.T) {
+	schema := graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}).Schema()
+	testCases := map[string]struct {
+		def    *ast.FieldDefinition
+		nested bool
+		want   int
+	}{
+		"success: root_field_with_directive": {
+			def:  schema.Query.Fields.ForName("user"),
+			want: 60,
+		},
+		"success: root_field_without_directive": {
+			def:  schema.Query.Fields.ForName("users"),
+			want: 0,
+		},
+		"success: nested_field_without_directive": {
+			def:    schema.Types["User"].Fields.ForName("name"),
+			nested: true,
+			want:   100,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Policy{}
+			p.Restrict(100)
+			ctx := NewContext(context.Background(), p)
+			if tt.nested {
+				ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{Object: "Query"})
+			}
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Field: graphql.CollectedField{Field: &ast.Field{Definition: tt.def}},
+			})
+			_, err := Extension{}.InterceptField(ctx, func(ctx context.Context) (interface{}, error) {
+				return nil, nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, p.MaxAge()); diff != "" {
+				t.Errorf("unexpected max age: %v", diff)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	const body = `{"data":{"user":null}}`
+	etag := newETag([]byte(body))
+
+	testCases := map[string]struct {
+		method           string
+		maxAge           int
+		status           int
+		principal        *auth.Principal
+		ifNoneMatch      string
+		wantStatus       int
+		wantCacheControl string
+		wantETag         string
+		wantVary         string
+		wantBody         string
+	}{
+		"success: public": {
+			method:           http.MethodGet,
+			maxAge:           60,
+			status:           http.StatusOK,
+			wantStatus:       http.StatusOK,
+			wantCacheControl: "public, max-age=60",
+			wantETag:         etag,
+			wantVary:         echo.HeaderAuthorization,
+			wantBody:         body,
+		},
+		"success: private_for_authenticated": {
+			method:           http.MethodGet,
+			maxAge:           60,
+			status:           http.StatusOK,
+			principal:        &auth.Principal{UserID: "user-1", Role: auth.RoleUser},
+			wantStatus:       http.StatusOK,
+			wantCacheControl: "private, max-age=60",
+			wantETag:         etag,
+			wantVary:         echo.HeaderAuthorization,
+			wantBody:         body,
+		},
+		"success: not_cacheable": {
+			method:           http.MethodGet,
+			maxAge:           0,
+			status:           http.StatusOK,
+			wantStatus:       http.StatusOK,
+			wantCacheControl: "no-store",
+			wantETag:         etag,
+			wantVary:         echo.HeaderAuthorization,
+			wantBody:         body,
+		},
+		"success: not_modified": {
+			method:           http.MethodGet,
+			maxAge:           60,
+			status:           http.StatusOK,
+			ifNoneMatch:      `"other", W/` + etag,
+			wantStatus:       http.StatusNotModified,
+			wantCacheControl: "public, max-age=60",
+			wantETag:         etag,
+			wantVary:         echo.HeaderAuthorization,
+		},
+		"success: error_status": {
+			method:           http.MethodGet,
+			maxAge:           60,
+			status:           http.StatusNotAcceptable,
+			wantStatus:       http.StatusNotAcceptable,
+			wantCacheControl: "no-store",
+			wantBody:         body,
+		},
+		"success: post_is_not_cached": {
+			method:     http.MethodPost,
+			maxAge:     60,
+			status:     http.StatusOK,
+			wantStatus: http.StatusOK,
+			wantBody:   body,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			// the status recorded by echo is used by the access log and request metrics
+			var recorded int
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					err := next(c)
+					recorded = c.Response().Status
+					return err
+				}
+			})
+			h := func(c echo.Context) error {
+				if p := FromContext(c.Request().Context()); p != nil {
+					p.Restrict(tt.maxAge)
+				}
+				return c.String(tt.status, body)
+			}
+			e.Add(tt.method, "/graphql", h, Middleware())
+
+			req := httptest.NewRequest(tt.method, "/graphql", nil)
+			req = req.WithContext(auth.NewContext(req.Context(), tt.principal))
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if diff := cmp.Diff(tt.wantStatus, rec.Code); diff != "" {
+				t.Errorf("unexpected status: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantStatus, recorded); diff != "" {
+				t.Errorf("unexpected recorded status: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantCacheControl, rec.Header().Get(echo.HeaderCacheControl)); diff != "" {
+				t.Errorf("unexpected cache control: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantETag, rec.Header().Get("ETag")); diff != "" {
+				t.Errorf("unexpected etag: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantVary, rec.Header().Get(echo.HeaderVary)); diff != "" {
+				t.Errorf("unexpected vary: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rec.Body.String()); diff != "" {
+				t.Errorf("unexpected body: %v", diff)
+			}
+		})
+	}
+}
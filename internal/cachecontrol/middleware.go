@@ -0,0 +1,95 @@
+package cachecontrol
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+)
+
+// Middleware sets Cache-Control and ETag to responses of GET requests
+// responses are buffered to hash the body, and 304 is returned when If-None-Match matches
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method != http.MethodGet || websocket.IsWebSocketUpgrade(req) {
+				return next(c)
+			}
+
+			p := &Policy{}
+			c.SetRequest(req.WithContext(NewContext(req.Context(), p)))
+			// the handler writes into a buffer through its own echo.Response,
+			// so the status and size of the original response are recorded only when it is sent
+			res := c.Response()
+			buf := &bufferedWriter{header: res.Header(), status: http.StatusOK}
+			c.SetResponse(echo.NewResponse(buf, c.Echo()))
+			err := next(c)
+			c.SetResponse(res)
+			if err != nil {
+				return err
+			}
+
+			if buf.status != http.StatusOK {
+				res.Header().Set(echo.HeaderCacheControl, "no-store")
+				res.WriteHeader(buf.status)
+				_, err = res.Write(buf.body.Bytes())
+				return err
+			}
+			etag := newETag(buf.body.Bytes())
+			res.Header().Set(echo.HeaderCacheControl, p.Header(auth.FromContext(req.Context()) != nil))
+			// public or private depends on the Authorization header, so shared caches must not mix them
+			res.Header().Add(echo.HeaderVary, echo.HeaderAuthorization)
+			res.Header().Set("ETag", etag)
+			if matchETag(req.Header.Get("If-None-Match"), etag) {
+				res.WriteHeader(http.StatusNotModified)
+				return nil
+			}
+			res.WriteHeader(http.StatusOK)
+			_, err = res.Write(buf.body.Bytes())
+			return err
+		}
+	}
+}
+
+// bufferedWriter holds the status and body until the cache policy is decided
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// newETag returns a strong ETag of the body
+func newETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchETag reports whether If-None-Match contains the ETag
+// https://www.rfc-editor.org/rfc/rfc9110#field.if-none-match
+func matchETag(ifNoneMatch, etag string) bool {
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == "*" || t == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -2,8 +2,13 @@ package internal
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/introspection"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/persisted"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/tracing"
 )
 
 // Config is the configuration for the API server.
@@ -11,12 +16,45 @@ type Config struct {
 	Port            int  `envconfig:"PORT" default:"8000"`
 	GracefulTimeout int  `envconfig:"GRACEFUL_TIMEOUT" default:"30"`
 	DebugMode       bool `envconfig:"DEBUG_MODE" default:"false"`
+	/// Graceful shutdown
+	ShutdownDrainDelay time.Duration `envconfig:"SHUTDOWN_DRAIN_DELAY" default:"5s"` // time to keep serving after readiness fails, included in GRACEFUL_TIMEOUT
 	/// DB
 	DatabaseUser     string `envconfig:"DATABASE_USER" required:"true"`
 	DatabasePassword string `envconfig:"DATABASE_PASSWORD" required:"true"`
 	DatabaseHost     string `envconfig:"DATABASE_HOST" required:"true"`
 	DatabaseName     string `envconfig:"DATABASE_NAME" required:"true"`
 	DatabasePort     int    `envconfig:"DATABASE_PORT" default:"5432"`
+	/// Request ID
+	SQLRequestIDComment bool `envconfig:"SQL_REQUEST_ID_COMMENT" default:"false"` // append request IDs to SQL, queries cost an extra round trip without the statement cache
+	/// DataLoader
+	DataLoaderWait     time.Duration `envconfig:"DATALOADER_WAIT" default:"2ms"`
+	DataLoaderMaxBatch int           `envconfig:"DATALOADER_MAX_BATCH" default:"100"`
+	/// Query limits
+	GraphQLMaxDepth      int `envconfig:"GRAPHQL_MAX_DEPTH" default:"10"`        // 0 is unlimited
+	GraphQLMaxComplexity int `envconfig:"GRAPHQL_MAX_COMPLEXITY" default:"1000"` // 0 is unlimited
+	/// Persisted queries
+	PersistedQueryMode      string `envconfig:"PERSISTED_QUERY_MODE" default:"apq"`        // apq or allowlist
+	PersistedQueryCache     string `envconfig:"PERSISTED_QUERY_CACHE" default:"memory"`    // memory or postgres, used in apq mode
+	PersistedQueryCacheSize int    `envconfig:"PERSISTED_QUERY_CACHE_SIZE" default:"1000"` // max number of queries in the cache
+	PersistedQueryManifest  string `envconfig:"PERSISTED_QUERY_MANIFEST"`                  // path of the manifest, used in allowlist mode
+	/// Introspection and playground
+	GraphQLIntrospection string `envconfig:"GRAPHQL_INTROSPECTION"` // enabled, disabled or admin (only for admin principals), enabled only in debug mode if empty
+	Playground           string `envconfig:"PLAYGROUND"`            // graphiql, sandbox or none, graphiql in debug mode if empty
+	PlaygroundPath       string `envconfig:"PLAYGROUND_PATH" default:"/"`
+	/// WebSocket
+	WebSocketInitTimeout  time.Duration `envconfig:"WEBSOCKET_INIT_TIMEOUT" default:"10s"` // timeout to receive connection_init
+	WebSocketKeepAlive    time.Duration `envconfig:"WEBSOCKET_KEEP_ALIVE" default:"10s"`   // interval of keepalive messages
+	WebSocketPingInterval time.Duration `envconfig:"WEBSOCKET_PING_INTERVAL" default:"0s"` // interval of ping requiring pong (0 is disabled)
+	/// Tracing
+	TracingExporter    string  `envconfig:"TRACING_EXPORTER" default:"none"` // none, stdout or otlp (configured by OTEL_EXPORTER_OTLP_* variables)
+	TracingServiceName string  `envconfig:"OTEL_SERVICE_NAME" default:"go-gql-sqlc-template"`
+	TracingSampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"` // ratio of sampled requests without sampled parents
+	/// Auth
+	JWTSecret       string `envconfig:"JWT_SECRET"`         // HS256
+	JWTRSAPublicKey string `envconfig:"JWT_RSA_PUBLIC_KEY"` // RS256, PEM encoded
+	JWTJWKSFile     string `envconfig:"JWT_JWKS_FILE"`      // RS256, path of a local JWKS file
+	JWTIssuer       string `envconfig:"JWT_ISSUER"`
+	JWTAudience     string `envconfig:"JWT_AUDIENCE"`
 }
 
 func (cnf *Config) DataSource() string {
@@ -24,10 +62,66 @@ func (cnf *Config) DataSource() string {
 		cnf.DatabaseUser, cnf.DatabasePassword, cnf.DatabaseHost, cnf.DatabasePort, cnf.DatabaseName)
 }
 
+func (cnf *Config) VerifierConfig() auth.VerifierConfig {
+	return auth.VerifierConfig{
+		HMACSecret:      []byte(cnf.JWTSecret),
+		RSAPublicKeyPEM: []byte(cnf.JWTRSAPublicKey),
+		JWKSFile:        cnf.JWTJWKSFile,
+		Issuer:          cnf.JWTIssuer,
+		Audience:        cnf.JWTAudience,
+	}
+}
+
+func (cnf *Config) PersistedQueryConfig() persisted.Config {
+	return persisted.Config{
+		Mode:         cnf.PersistedQueryMode,
+		Cache:        cnf.PersistedQueryCache,
+		CacheSize:    cnf.PersistedQueryCacheSize,
+		ManifestFile: cnf.PersistedQueryManifest,
+	}
+}
+
+func (cnf *Config) TracingConfig() tracing.Config {
+	return tracing.Config{
+		Exporter:    cnf.TracingExporter,
+		ServiceName: cnf.TracingServiceName,
+		SampleRatio: cnf.TracingSampleRatio,
+	}
+}
+
+// IntrospectionMode returns the introspection mode
+// introspection is enabled only in debug mode unless it is configured explicitly
+func (cnf *Config) IntrospectionMode() string {
+	if cnf.GraphQLIntrospection != "" {
+		return cnf.GraphQLIntrospection
+	}
+	if cnf.DebugMode {
+		return introspection.ModeEnabled
+	}
+	return introspection.ModeDisabled
+}
+
+// PlaygroundConfig returns the playground to serve
+// the playground is served only in debug mode unless it is configured explicitly
+func (cnf *Config) PlaygroundConfig() PlaygroundConfig {
+	kind := cnf.Playground
+	if kind == "" {
+		kind = PlaygroundNone
+		if cnf.DebugMode {
+			kind = PlaygroundGraphiQL
+		}
+	}
+	return PlaygroundConfig{Kind: kind, Path: cnf.PlaygroundPath}
+}
+
 func NewConfig() (*Config, error) {
 	conf := &Config{}
 	if err := envconfig.Process("", conf); err != nil {
 		return nil, err
 	}
+	// the server is started in a goroutine, so invalid values are rejected before it
+	if _, err := newPlaygroundHandler(conf.PlaygroundConfig().Kind, ""); err != nil {
+		return nil, err
+	}
 	return conf, nil
 }
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/introspection"
+)
+
+func TestNewConfig(t *testing.T) {
+	type expectedConfig struct {
+		IntrospectionMode string
+		Playground        string
+	}
+
+	testCases := map[string]struct {
+		env     map[string]string
+		want    expectedConfig
+		wantErr bool
+	}{
+		"success: production_defaults": {
+			env:  map[string]string{},
+			want: expectedConfig{IntrospectionMode: introspection.ModeDisabled, Playground: PlaygroundNone},
+		},
+		"success: debug_defaults": {
+			env:  map[string]string{"DEBUG_MODE": "true"},
+			want: expectedConfig{IntrospectionMode: introspection.ModeEnabled, Playground: PlaygroundGraphiQL},
+		},
+		"success: explicit": {
+			env:  map[string]string{"GRAPHQL_INTROSPECTION": "admin", "PLAYGROUND": "sandbox"},
+			want: expectedConfig{IntrospectionMode: introspection.ModeAdmin, Playground: PlaygroundSandbox},
+		},
+		"failure: unknown_playground": {
+			env:     map[string]string{"PLAYGROUND": "altair"},
+			wantErr: true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			// environment variables are shared, so tests are not parallel
+			t.Setenv("DATABASE_USER", "user")
+			t.Setenv("DATABASE_PASSWORD", "password")
+			t.Setenv("DATABASE_HOST", "localhost")
+			t.Setenv("DATABASE_NAME", "db")
+			env := map[string]string{"DEBUG_MODE": "false", "GRAPHQL_INTROSPECTION": "", "PLAYGROUND": ""}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+
+			cnf, err := NewConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := expectedConfig{IntrospectionMode: cnf.IntrospectionMode(), Playground: cnf.PlaygroundConfig().Kind}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected config: %v", diff)
+			}
+		})
+	}
+}
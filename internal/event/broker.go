@@ -0,0 +1,145 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+)
+
+// Channel is a channel of Postgres LISTEN/NOTIFY
+type Channel string
+
+const (
+	// UserCreated is notified with the ID of a created user
+	UserCreated Channel = "user_created"
+	// UserUpdated is notified with the ID of an updated user
+	UserUpdated Channel = "user_updated"
+)
+
+// channels are listened by Broker
+var channels = []Channel{UserCreated, UserUpdated}
+
+const (
+	// bufferSize is the number of events buffered for each subscriber
+	// events are dropped for slow subscribers
+	bufferSize = 16
+	// retryInterval is the interval to listen again after the connection is lost
+	retryInterval = 3 * time.Second
+)
+
+// Notifier publishes events
+// it is implemented by db.Queries
+type Notifier interface {
+	NotifyEvent(ctx context.Context, arg db.NotifyEventParams) error
+}
+
+// Broker delivers events through Postgres LISTEN/NOTIFY
+// every replica listens the same channels, so events published by any replica reach all subscribers
+type Broker struct {
+	pool     *pgxpool.Pool
+	notifier Notifier
+
+	mu          sync.Mutex
+	subscribers map[Channel]map[chan string]struct{}
+}
+
+// NewBroker is a constructor for Broker
+func NewBroker(pool *pgxpool.Pool, notifier Notifier) *Broker {
+	return &Broker{
+		pool:        pool,
+		notifier:    notifier,
+		subscribers: make(map[Channel]map[chan string]struct{}),
+	}
+}
+
+// Publish notifies a payload to the channel
+func (b *Broker) Publish(ctx context.Context, ch Channel, payload string) error {
+	if err := b.notifier.NotifyEvent(ctx, db.NotifyEventParams{Channel: string(ch), Payload: payload}); err != nil {
+		return fmt.Errorf("failed to notify %s: %w", ch, err)
+	}
+	return nil
+}
+
+// Subscribe returns payloads notified to the channel
+// the returned channel is closed when ctx is done
+func (b *Broker) Subscribe(ctx context.Context, ch Channel) <-chan string {
+	c := make(chan string, bufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[ch] == nil {
+		b.subscribers[ch] = make(map[chan string]struct{})
+	}
+	b.subscribers[ch][c] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers[ch], c)
+		close(c)
+	}()
+	return c
+}
+
+// Run listens channels until ctx is done
+// it holds a connection of the pool, and listens again when the connection is lost
+func (b *Broker) Run(ctx context.Context) {
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.WarnContext(ctx, "failed to listen events", "error", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func (b *Broker) listen(ctx context.Context) error {
+	pooled, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// the connection is taken out of the pool, so the session with LISTEN is never reused by queries
+	conn := pooled.Hijack()
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	for _, ch := range channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{string(ch)}.Sanitize()); err != nil {
+			return fmt.Errorf("failed to listen %s: %w", ch, err)
+		}
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+		b.dispatch(Channel(n.Channel), n.Payload)
+	}
+}
+
+// dispatch sends a payload to subscribers of the channel without blocking
+func (b *Broker) dispatch(ch Channel, payload string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c := range b.subscribers[ch] {
+		select {
+		case c <- payload:
+		default:
+			slog.Warn("event is dropped for a slow subscriber", "channel", ch)
+		}
+	}
+}
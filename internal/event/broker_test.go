@@ -0,0 +1,42 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSubscribe(t *testing.T) {
+	b := NewBroker(nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	created := b.Subscribe(ctx, UserCreated)
+	updated := b.Subscribe(ctx, UserUpdated)
+
+	// payloads are delivered to subscribers of the channel
+	b.dispatch(UserCreated, "user-1")
+	b.dispatch(UserUpdated, "user-2")
+	if diff := cmp.Diff("user-1", <-created); diff != "" {
+		t.Errorf("unexpected payload: %v", diff)
+	}
+	if diff := cmp.Diff("user-2", <-updated); diff != "" {
+		t.Errorf("unexpected payload: %v", diff)
+	}
+
+	// payloads are dropped instead of blocking when the buffer is full
+	for i := 0; i < bufferSize+1; i++ {
+		b.dispatch(UserCreated, "user-3")
+	}
+	if diff := cmp.Diff(bufferSize, len(created)); diff != "" {
+		t.Errorf("unexpected buffered payloads: %v", diff)
+	}
+
+	// channels are closed when ctx is done
+	cancel()
+	for range created {
+	}
+	for range updated {
+	}
+	b.dispatch(UserCreated, "user-4")
+}
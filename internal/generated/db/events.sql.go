@@ -0,0 +1,26 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: events.sql
+
+package db
+
+import (
+	"context"
+)
+
+const notifyEvent = `-- name: NotifyEvent :exec
+SELECT /* events_001 */
+    pg_notify($1::text, $2::text)
+`
+
+type NotifyEventParams struct {
+	Channel string
+	Payload string
+}
+
+// listeners on every replica receive the payload
+func (q *Queries) NotifyEvent(ctx context.Context, arg NotifyEventParams) error {
+	_, err := q.db.Exec(ctx, notifyEvent, arg.Channel, arg.Payload)
+	return err
+}
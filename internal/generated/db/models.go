@@ -5,9 +5,19 @@
 package db
 
 import (
-	"github.com/jackc/pgx/v5/pgtype"
+	"time"
 )
 
+// Automatic Persisted Queries
+type PersistedQuery struct {
+	// SHA-256 Hash of Query
+	Hash string
+	// Query Document
+	Query string
+	// Creation Date
+	CreatedAt time.Time
+}
+
 type SchemaMigration struct {
 	Version string
 }
@@ -21,7 +31,9 @@ type User struct {
 	// Email Address
 	Email string
 	// Creation Date
-	CreatedAt pgtype.Timestamp
+	CreatedAt time.Time
 	// Last Update Date
-	UpdatedAt pgtype.Timestamp
+	UpdatedAt time.Time
+	// Deletion Date
+	DeletedAt *time.Time
 }
@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: persisted_queries.sql
+
+package db
+
+import (
+	"context"
+)
+
+const findPersistedQuery = `-- name: FindPersistedQuery :one
+SELECT /* persisted_queries_001 */
+    query
+FROM persisted_queries
+WHERE hash = $1
+`
+
+func (q *Queries) FindPersistedQuery(ctx context.Context, hash string) (string, error) {
+	row := q.db.QueryRow(ctx, findPersistedQuery, hash)
+	var query string
+	err := row.Scan(&query)
+	return query, err
+}
+
+const insertPersistedQuery = `-- name: InsertPersistedQuery :exec
+INSERT INTO persisted_queries /* persisted_queries_002 */
+(hash, query)
+SELECT $1, $2
+WHERE (SELECT count(*) FROM persisted_queries) < $3::BIGINT
+ON CONFLICT (hash) DO NOTHING
+`
+
+type InsertPersistedQueryParams struct {
+	Hash    string
+	Query   string
+	MaxRows int64
+}
+
+// the same hash always has the same query, so conflicts are ignored
+// queries are not inserted when the table already has max_rows rows
+func (q *Queries) InsertPersistedQuery(ctx context.Context, arg InsertPersistedQueryParams) error {
+	_, err := q.db.Exec(ctx, insertPersistedQuery, arg.Hash, arg.Query, arg.MaxRows)
+	return err
+}
@@ -0,0 +1,25 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: schema_migrations.sql
+
+package db
+
+import (
+	"context"
+)
+
+const findLatestMigrationVersion = `-- name: FindLatestMigrationVersion :one
+SELECT /* schema_migrations_001 */
+    version
+FROM schema_migrations
+ORDER BY version DESC
+LIMIT 1
+`
+
+func (q *Queries) FindLatestMigrationVersion(ctx context.Context) (string, error) {
+	row := q.db.QueryRow(ctx, findLatestMigrationVersion)
+	var version string
+	err := row.Scan(&version)
+	return version, err
+}
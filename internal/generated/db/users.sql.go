@@ -11,13 +11,20 @@ import (
 
 const findUserByID = `-- name: FindUserByID :one
 SELECT /* users_002 */
-    id, user_name, email, created_at, updated_at
+    id, user_name, email, created_at, updated_at, deleted_at
 FROM users
 WHERE id = $1
+    AND (deleted_at IS NULL OR $2::boolean)
 `
 
-func (q *Queries) FindUserByID(ctx context.Context, id string) (User, error) {
-	row := q.db.QueryRow(ctx, findUserByID, id)
+type FindUserByIDParams struct {
+	ID             string
+	IncludeDeleted bool
+}
+
+// soft-deleted users are excluded unless include_deleted is true
+func (q *Queries) FindUserByID(ctx context.Context, arg FindUserByIDParams) (User, error) {
+	row := q.db.QueryRow(ctx, findUserByID, arg.ID, arg.IncludeDeleted)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -25,14 +32,51 @@ func (q *Queries) FindUserByID(ctx context.Context, id string) (User, error) {
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const findUsersByIDs = `-- name: FindUsersByIDs :many
+SELECT /* users_008 */
+    id, user_name, email, created_at, updated_at, deleted_at
+FROM users
+WHERE id = ANY($1::bpchar[])
+    AND deleted_at IS NULL
+`
+
+// soft-deleted users are excluded
+func (q *Queries) FindUsersByIDs(ctx context.Context, ids []string) ([]User, error) {
+	rows, err := q.db.Query(ctx, findUsersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserName,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertUser = `-- name: InsertUser :one
 INSERT INTO users /* users_001 */
 (id, user_name, email) VALUES ($1, $2, $3)
-RETURNING id, user_name, email, created_at, updated_at
+RETURNING id, user_name, email, created_at, updated_at, deleted_at
 `
 
 type InsertUserParams struct {
@@ -50,6 +94,192 @@ func (q *Queries) InsertUser(ctx context.Context, arg InsertUserParams) (User, e
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const listUsersAscending = `-- name: ListUsersAscending :many
+SELECT /* users_006 */
+    id, user_name, email, created_at, updated_at, deleted_at
+FROM users
+WHERE id > COALESCE($1::bpchar, '')
+    AND id < COALESCE($2::bpchar, 'g')
+    AND (deleted_at IS NULL OR $3::boolean)
+ORDER BY id ASC
+LIMIT $4
+`
+
+type ListUsersAscendingParams struct {
+	LowerID        *string
+	UpperID        *string
+	IncludeDeleted bool
+	Limit          int32
+}
+
+// keyset pagination on id (UUIDv7 is time-ordered)
+// missing bounds are replaced with an empty string and 'g', which enclose all lowercase UUIDs
+// so both bounds stay index range conditions in generic plans of prepared statements
+func (q *Queries) ListUsersAscending(ctx context.Context, arg ListUsersAscendingParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersAscending,
+		arg.LowerID,
+		arg.UpperID,
+		arg.IncludeDeleted,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserName,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersDescending = `-- name: ListUsersDescending :many
+SELECT /* users_007 */
+    id, user_name, email, created_at, updated_at, deleted_at
+FROM users
+WHERE id > COALESCE($1::bpchar, '')
+    AND id < COALESCE($2::bpchar, 'g')
+    AND (deleted_at IS NULL OR $3::boolean)
+ORDER BY id DESC
+LIMIT $4
+`
+
+type ListUsersDescendingParams struct {
+	LowerID        *string
+	UpperID        *string
+	IncludeDeleted bool
+	Limit          int32
+}
+
+// keyset pagination on id (UUIDv7 is time-ordered)
+// missing bounds are replaced with an empty string and 'g', which enclose all lowercase UUIDs
+// so both bounds stay index range conditions in generic plans of prepared statements
+func (q *Queries) ListUsersDescending(ctx context.Context, arg ListUsersDescendingParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersDescending,
+		arg.LowerID,
+		arg.UpperID,
+		arg.IncludeDeleted,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserName,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users /* users_005 */
+SET
+    deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+    AND deleted_at IS NOT NULL
+RETURNING id, user_name, email, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :one
+UPDATE users /* users_004 */
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+    AND deleted_at IS NULL
+RETURNING id, user_name, email, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRow(ctx, softDeleteUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users /* users_003 */
+SET
+    user_name = COALESCE($1, user_name),
+    email = COALESCE($2, email),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $3
+    AND deleted_at IS NULL
+RETURNING id, user_name, email, created_at, updated_at, deleted_at
+`
+
+type UpdateUserParams struct {
+	UserName *string
+	Email    *string
+	ID       string
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUser, arg.UserName, arg.Email, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
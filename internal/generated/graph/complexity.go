@@ -0,0 +1,35 @@
+package graph
+
+import (
+	"github.com/rikeda71/go-gql-sqlc-template/internal/pagination"
+)
+
+// This file will not be regenerated automatically.
+//
+// It defines complexity functions of fields whose cost depends on arguments.
+
+// NewComplexityRoot returns complexity functions of fields
+// fields which are not set here cost 1 + the cost of their children
+func NewComplexityRoot() ComplexityRoot {
+	var c ComplexityRoot
+	c.Query.Nodes = func(childComplexity int, ids []string) int {
+		return 1 + len(ids)*childComplexity
+	}
+	c.Query.Users = func(childComplexity int, first *int, after *string, last *int, before *string, orderBy *UserOrder, includeDeleted bool) int {
+		return 1 + pageSize(first, last)*childComplexity
+	}
+	return c
+}
+
+// pageSize returns the number of items requested to a connection field
+// invalid values are counted as 0 because they are rejected by the resolver
+func pageSize(first, last *int) int {
+	n := pagination.DefaultLimit
+	switch {
+	case first != nil:
+		n = *first
+	case last != nil:
+		n = *last
+	}
+	return max(n, 0)
+}
@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+)
+
+// This file will not be regenerated automatically.
+//
+// It enforces @constraint on inputs before resolvers run.
+
+type problemsCtxKey struct{}
+
+// problemCollector collects problems of arguments for each field in an operation
+// arguments are parsed before the field is resolved, so problems are keyed by the field context
+type problemCollector struct {
+	mu       sync.Mutex
+	problems map[*graphql.FieldContext][]*Problem
+}
+
+func (c *problemCollector) add(fc *graphql.FieldContext, p *Problem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.problems[fc] = append(c.problems[fc], p)
+}
+
+func (c *problemCollector) take(fc *graphql.FieldContext) []*Problem {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	problems := c.problems[fc]
+	delete(c.problems, fc)
+	return problems
+}
+
+// NewConstraintContext returns a context collecting problems of @constraint in an operation
+func NewConstraintContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, problemsCtxKey{}, &problemCollector{
+		problems: make(map[*graphql.FieldContext][]*Problem),
+	})
+}
+
+// problemOutputs returns outputs of mutations which report problems instead of errors
+// keys are names of GraphQL output types
+var problemOutputs = map[string]func(problems []*Problem) interface{}{
+	"CreateUserOutput": func(problems []*Problem) interface{} { return newCreateUserProblemsOutput(problems) },
+	"UpdateUserOutput": func(problems []*Problem) interface{} { return newUpdateUserProblemsOutput(problems) },
+}
+
+// ConstraintMiddleware short-circuits fields whose arguments have problems
+// outputs in problemOutputs are returned with the problems, otherwise the first problem is a VALIDATION_ERROR
+func ConstraintMiddleware(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	c, ok := ctx.Value(problemsCtxKey{}).(*problemCollector)
+	if !ok {
+		return next(ctx)
+	}
+	fc := graphql.GetFieldContext(ctx)
+	problems := c.take(fc)
+	if len(problems) == 0 {
+		return next(ctx)
+	}
+	if newOutput, ok := problemOutputs[fc.Field.Definition.Type.Name()]; ok {
+		return newOutput(problems), nil
+	}
+	return nil, apperr.New(apperr.CodeValidation, problems[0].Field, problems[0].Message)
+}
+
+// ConstraintDirective validates a string input against @constraint
+// problems are collected without failing, so that all problems of the input are reported at once
+func ConstraintDirective(ctx context.Context, obj interface{}, next graphql.Resolver, minLength *int, maxLength *int, pattern *string, format *ConstraintFormat) (interface{}, error) {
+	v, err := next(ctx)
+	if err != nil {
+		return v, err
+	}
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case *string:
+		if t == nil {
+			return v, nil
+		}
+		s = *t
+	default:
+		return v, fmt.Errorf("@constraint is not applicable to %T", v)
+	}
+
+	fc := graphql.GetFieldContext(ctx)
+	field := graphql.GetPath(ctx)[len(fc.Path()):].String()
+	problem := checkConstraint(field, s, minLength, maxLength, pattern, format)
+	if problem == nil {
+		return v, nil
+	}
+	c, ok := ctx.Value(problemsCtxKey{}).(*problemCollector)
+	if !ok {
+		return v, apperr.New(apperr.CodeValidation, problem.Field, problem.Message)
+	}
+	c.add(fc, problem)
+	return v, nil
+}
+
+// checkConstraint returns the first problem of s
+func checkConstraint(field string, s string, minLength *int, maxLength *int, pattern *string, format *ConstraintFormat) *Problem {
+	length := utf8.RuneCountInString(s)
+	switch {
+	case minLength != nil && length < *minLength:
+		return &Problem{Field: field, Code: ProblemCodeTooShort, Message: fmt.Sprintf("%s must be at least %d characters", field, *minLength)}
+	case maxLength != nil && length > *maxLength:
+		return &Problem{Field: field, Code: ProblemCodeTooLong, Message: fmt.Sprintf("%s must be at most %d characters", field, *maxLength)}
+	case pattern != nil && !compilePattern(*pattern).MatchString(s):
+		return &Problem{Field: field, Code: ProblemCodePatternMismatch, Message: fmt.Sprintf("%s must match %s", field, *pattern)}
+	case format != nil && !matchFormat(*format, s):
+		return &Problem{Field: field, Code: ProblemCodeInvalidFormat, Message: fmt.Sprintf("%s must be a valid %s", field, *format)}
+	}
+	return nil
+}
+
+// patterns caches compiled patterns of the schema
+var patterns sync.Map
+
+// compilePattern compiles a pattern of the schema, it panics on invalid patterns as a bug of the schema
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patterns.Store(pattern, re)
+	return re
+}
+
+func matchFormat(format ConstraintFormat, s string) bool {
+	var err error
+	switch format {
+	case ConstraintFormatEmail:
+		_, err = UnmarshalEmail(s)
+	case ConstraintFormatUUID:
+		_, err = UnmarshalUUID(s)
+	}
+	return err == nil
+}
@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckConstraint(t *testing.T) {
+	one, three := 1, 3
+	pattern := `\S`
+	email, uuid := ConstraintFormatEmail, ConstraintFormatUUID
+
+	testCases := map[string]struct {
+		input     string
+		minLength *int
+		maxLength *int
+		pattern   *string
+		format    *ConstraintFormat
+		want      *ProblemCode
+	}{
+		"success: no_constraint": {
+			input: "",
+		},
+		"success: length_in_characters": {
+			input:     "日本語",
+			minLength: &one,
+			maxLength: &three,
+		},
+		"success: pattern_matches_part": {
+			input:   " a ",
+			pattern: &pattern,
+		},
+		"success: email": {
+			input:  "user@example.com",
+			format: &email,
+		},
+		"success: uuid": {
+			input:  "01890a5d-ac96-774b-bcce-b302099a8057",
+			format: &uuid,
+		},
+		"failure: too_short": {
+			input:     "",
+			minLength: &one,
+			want:      ptr(ProblemCodeTooShort),
+		},
+		"failure: too_long": {
+			input:     "日本語で",
+			maxLength: &three,
+			want:      ptr(ProblemCodeTooLong),
+		},
+		"failure: pattern_mismatch": {
+			input:   "   ",
+			pattern: &pattern,
+			want:    ptr(ProblemCodePatternMismatch),
+		},
+		"failure: invalid_email": {
+			input:  "user",
+			format: &email,
+			want:   ptr(ProblemCodeInvalidFormat),
+		},
+		"failure: invalid_uuid": {
+			input:  "not-a-uuid",
+			format: &uuid,
+			want:   ptr(ProblemCodeInvalidFormat),
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got := checkConstraint("input.name", tt.input, tt.minLength, tt.maxLength, tt.pattern, tt.format)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("unexpected problem: %v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("problem should be returned")
+			}
+			if diff := cmp.Diff(*tt.want, got.Code); diff != "" {
+				t.Errorf("unexpected code: %v", diff)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestConstraintMiddleware(t *testing.T) {
+	// resolvers are not called, so the database is not needed
+	srv := handler.New(NewExecutableSchema(Config{
+		Resolvers: &Resolver{},
+		Directives: DirectiveRoot{
+			Auth:       AuthDirective,
+			Owner:      OwnerDirective,
+			Constraint: ConstraintDirective,
+		},
+	}))
+	srv.AddTransport(transport.POST{})
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(NewConstraintContext(ctx))
+	})
+	srv.AroundFields(ConstraintMiddleware)
+
+	query := `mutation($input: CreateUserInput!) {
+		createUser(input: $input) { status errorField problems { field code } }
+	}`
+	body, err := json.Marshal(map[string]interface{}{
+		"query": query,
+		"variables": map[string]interface{}{
+			"input": map[string]interface{}{"name": "   ", "email": "user@" + strings.Repeat("a", 50) + "." + strings.Repeat("b", 50) + ".com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var got struct {
+		Data struct {
+			CreateUser struct {
+				Status     MutationStatus
+				ErrorField *string
+				Problems   []struct {
+					Field string
+					Code  ProblemCode
+				}
+			}
+		}
+		Errors []interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(got.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", got.Errors)
+	}
+	if diff := cmp.Diff(MutationStatusValidationError, got.Data.CreateUser.Status); diff != "" {
+		t.Errorf("unexpected status: %v", diff)
+	}
+	if diff := cmp.Diff(ptr("input.name"), got.Data.CreateUser.ErrorField); diff != "" {
+		t.Errorf("unexpected error field: %v", diff)
+	}
+	// all problems of the input are reported
+	wantProblems := []struct {
+		Field string
+		Code  ProblemCode
+	}{
+		{Field: "input.name", Code: ProblemCodePatternMismatch},
+		{Field: "input.email", Code: ProblemCodeTooLong},
+	}
+	if diff := cmp.Diff(wantProblems, got.Data.CreateUser.Problems); diff != "" {
+		t.Errorf("unexpected problems: %v", diff)
+	}
+}
@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/globalid"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/pagination"
+)
+
+// This file will not be regenerated automatically.
+//
+// It converts models of the database into models of GraphQL.
+
+// newUser converts db.User into User
+func newUser(u db.User) *User {
+	return &User{
+		ID:         globalid.Encode(nodeTypeUser, u.ID),
+		DatabaseID: u.ID,
+		Name:       u.UserName,
+		Email:      &u.Email,
+		CreatedAt:  u.CreatedAt,
+		UpdatedAt:  u.UpdatedAt,
+	}
+}
+
+// newUserConnection converts a page of db.User into UserConnection
+func newUserConnection(rows []db.User, info pagination.PageInfo) *UserConnection {
+	edges := make([]*UserEdge, 0, len(rows))
+	for _, u := range rows {
+		edges = append(edges, &UserEdge{
+			Cursor: pagination.EncodeCursor(u.ID),
+			Node:   newUser(u),
+		})
+	}
+	pageInfo := &PageInfo{
+		HasPreviousPage: info.HasPreviousPage,
+		HasNextPage:     info.HasNextPage,
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = &edges[0].Cursor
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+	return &UserConnection{Edges: edges, PageInfo: pageInfo}
+}
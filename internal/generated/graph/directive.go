@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+)
+
+// This file will not be regenerated automatically.
+//
+// It implements directives declared in schema/directives.graphql.
+// fields with these directives must be nullable so that unauthorized access nulls only the field.
+// root mutation fields are kept non-null for compatibility, so unauthorized mutations null the whole data.
+
+// ownedObject is an object which has an owner
+type ownedObject interface {
+	// ownerID returns the ID of the user who owns the object
+	ownerID() string
+}
+
+func (u *User) ownerID() string {
+	return u.DatabaseID
+}
+
+// AuthDirective implements @auth(requires: Role)
+func AuthDirective(ctx context.Context, _ interface{}, next graphql.Resolver, requires Role) (interface{}, error) {
+	p := auth.FromContext(ctx)
+	if p == nil {
+		return nil, apperr.New(apperr.CodeUnauthenticated, "", "authentication required")
+	}
+	if requires == RoleAdmin && !p.IsAdmin() {
+		return nil, apperr.New(apperr.CodeForbidden, "", "admin role required")
+	}
+	return next(ctx)
+}
+
+// OwnerDirective implements @owner
+func OwnerDirective(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	p := auth.FromContext(ctx)
+	if p.IsAdmin() {
+		return next(ctx)
+	}
+	owned, ok := obj.(ownedObject)
+	if p == nil || !ok || owned.ownerID() != p.UserID {
+		return nil, apperr.New(apperr.CodeForbidden, "", "only the owner can access the field")
+	}
+	return next(ctx)
+}
+
+// authorizeUser returns a FORBIDDEN error unless the principal is the user or an administrator
+// it is the check of @owner for users given by arguments, which are not resolved objects
+func authorizeUser(ctx context.Context, field string, userID string) error {
+	p := auth.FromContext(ctx)
+	if p.IsAdmin() || (p != nil && p.UserID == userID) {
+		return nil
+	}
+	return apperr.New(apperr.CodeForbidden, field, "only the user or administrators can modify the user")
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+)
+
+// This file will not be regenerated automatically.
+//
+// It converts domain errors into the output of mutations.
+
+// userConstraintFields maps constraints of the users table to input fields
+var userConstraintFields = map[string]string{
+	"users_user_name_key":   "input.name",
+	"users_email_key":       "input.email",
+	"users_email_lower_key": "input.email",
+}
+
+// mutationStatusOf converts the code of err into MutationStatus
+func mutationStatusOf(err *apperr.Error) MutationStatus {
+	switch err.Code {
+	case apperr.CodeAlreadyExists:
+		return MutationStatusAlreadyExists
+	case apperr.CodeNotFound:
+		return MutationStatusNotFound
+	case apperr.CodeValidation:
+		return MutationStatusValidationError
+	default:
+		return MutationStatusFailure
+	}
+}
+
+// errorFieldOf returns the input field path of err, or nil if err is not related to a field
+func errorFieldOf(err *apperr.Error) *string {
+	if err.Field == "" {
+		return nil
+	}
+	return &err.Field
+}
+
+// problemsOf converts a validation error into problems
+// validations of resolvers are not categorized, so the code is INVALID
+func problemsOf(err *apperr.Error) []*Problem {
+	if err.Code != apperr.CodeValidation {
+		return []*Problem{}
+	}
+	return []*Problem{{Field: err.Field, Code: ProblemCodeInvalid, Message: err.Message}}
+}
+
+// newCreateUserErrorOutput is a constructor for CreateUserOutput which represents a failure
+func newCreateUserErrorOutput(err *apperr.Error) *CreateUserOutput {
+	return &CreateUserOutput{
+		Status:       mutationStatusOf(err),
+		ErrorMessage: &err.Message,
+		ErrorField:   errorFieldOf(err),
+		Problems:     problemsOf(err),
+	}
+}
+
+// newCreateUserProblemsOutput is a constructor for CreateUserOutput which represents violations of @constraint
+func newCreateUserProblemsOutput(problems []*Problem) *CreateUserOutput {
+	return &CreateUserOutput{
+		Status:       MutationStatusValidationError,
+		ErrorMessage: &problems[0].Message,
+		ErrorField:   &problems[0].Field,
+		Problems:     problems,
+	}
+}
+
+// newUpdateUserErrorOutput is a constructor for UpdateUserOutput which represents a failure
+func newUpdateUserErrorOutput(err *apperr.Error) *UpdateUserOutput {
+	return &UpdateUserOutput{
+		Status:       mutationStatusOf(err),
+		ErrorMessage: &err.Message,
+		ErrorField:   errorFieldOf(err),
+		Problems:     problemsOf(err),
+	}
+}
+
+// newUpdateUserProblemsOutput is a constructor for UpdateUserOutput which represents violations of @constraint
+func newUpdateUserProblemsOutput(problems []*Problem) *UpdateUserOutput {
+	return &UpdateUserOutput{
+		Status:       MutationStatusValidationError,
+		ErrorMessage: &problems[0].Message,
+		ErrorField:   &problems[0].Field,
+		Problems:     problems,
+	}
+}
+
+// newDeleteUserErrorOutput is a constructor for DeleteUserOutput which represents a failure
+func newDeleteUserErrorOutput(err *apperr.Error) *DeleteUserOutput {
+	return &DeleteUserOutput{
+		Status:       mutationStatusOf(err),
+		ErrorMessage: &err.Message,
+	}
+}
+
+// newRestoreUserErrorOutput is a constructor for RestoreUserOutput which represents a failure
+func newRestoreUserErrorOutput(err *apperr.Error) *RestoreUserOutput {
+	return &RestoreUserOutput{
+		Status:       mutationStatusOf(err),
+		ErrorMessage: &err.Message,
+	}
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/event"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+)
+
+// This file will not be regenerated automatically.
+//
+// It publishes and streams events of subscriptions.
+
+// publishUser notifies the ID of a changed user
+// events are best-effort, so failures are logged without failing the mutation
+func (r *Resolver) publishUser(ctx context.Context, ch event.Channel, userID string) {
+	if err := r.Broker.Publish(ctx, ch, userID); err != nil {
+		slog.WarnContext(ctx, "failed to publish user event", "error", err.Error(), "id", userID)
+	}
+}
+
+// streamUsers loads users of notified IDs until ctx is done
+// the latest state is loaded without dataloaders because a subscription lives long,
+// and users deleted before they are loaded are skipped unless includeDeleted is true
+func (r *Resolver) streamUsers(ctx context.Context, ids <-chan string, includeDeleted bool) <-chan *User {
+	users := make(chan *User)
+	go func() {
+		defer close(users)
+		for id := range ids {
+			u, err := r.DBClient.FindUserByID(ctx, db.FindUserByIDParams{ID: id, IncludeDeleted: includeDeleted})
+			if err != nil {
+				if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+					slog.ErrorContext(ctx, "failed to find user of event", "error", err.Error(), "id", id)
+				}
+				continue
+			}
+			select {
+			case users <- newUser(u):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return users
+}
+
+// filterUser passes only events of the user
+func filterUser(ctx context.Context, ids <-chan string, userID string) <-chan string {
+	filtered := make(chan string)
+	go func() {
+		defer close(filtered)
+		for id := range ids {
+			if id != userID {
+				continue
+			}
+			select {
+			case filtered <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return filtered
+}
@@ -6,28 +6,62 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 )
 
+// An object with a global ID
+// https://relay.dev/graphql/objectidentification.htm
+type Node interface {
+	IsNode()
+	// global ID
+	GetID() string
+}
+
 // Create User Input
 type CreateUserInput struct {
-	// 名前
+	// User Name
 	Name string `json:"name"`
-	// メールアドレス
+	// Email Address
 	Email string `json:"email"`
 }
 
 // Create User Output
 type CreateUserOutput struct {
-	// 作成した結果
+	// status
 	Status MutationStatus `json:"status"`
 	// error message
 	ErrorMessage *string `json:"errorMessage,omitempty"`
+	// path of the input field which caused the error (ex. "input.email")
+	ErrorField *string `json:"errorField,omitempty"`
+	// problems of input fields when status is VALIDATION_ERROR
+	Problems []*Problem `json:"problems"`
 	// metadata
 	Metadata *CreateUserOutputMetadata `json:"metadata,omitempty"`
 }
 
 type CreateUserOutputMetadata struct {
-	// 作成したユーザ情報
+	// Created User Information
+	User *User `json:"user,omitempty"`
+}
+
+// Delete User Input
+type DeleteUserInput struct {
+	// User ID (global ID or database ID)
+	ID string `json:"id"`
+}
+
+// Delete User Output
+type DeleteUserOutput struct {
+	// status
+	Status MutationStatus `json:"status"`
+	// error message
+	ErrorMessage *string `json:"errorMessage,omitempty"`
+	// metadata
+	Metadata *DeleteUserOutputMetadata `json:"metadata,omitempty"`
+}
+
+type DeleteUserOutputMetadata struct {
+	// Deleted User Information
 	User *User `json:"user,omitempty"`
 }
 
@@ -35,44 +69,204 @@ type CreateUserOutputMetadata struct {
 type Mutation struct {
 }
 
+// Information about pagination in a connection
+// https://relay.dev/graphql/connections.htm#sec-undefined.PageInfo
+type PageInfo struct {
+	// whether more items exist before startCursor
+	HasPreviousPage bool `json:"hasPreviousPage"`
+	// whether more items exist after endCursor
+	HasNextPage bool `json:"hasNextPage"`
+	// cursor of the first edge
+	StartCursor *string `json:"startCursor,omitempty"`
+	// cursor of the last edge
+	EndCursor *string `json:"endCursor,omitempty"`
+}
+
+// A problem of an input field
+type Problem struct {
+	// path of the input field (ex. "input.name")
+	Field string `json:"field"`
+	// kind of the problem
+	Code ProblemCode `json:"code"`
+	// human readable message
+	Message string `json:"message"`
+}
+
 // Query
 type Query struct {
 }
 
+// Restore User Input
+type RestoreUserInput struct {
+	// User ID (global ID or database ID)
+	ID string `json:"id"`
+}
+
+// Restore User Output
+type RestoreUserOutput struct {
+	// status
+	Status MutationStatus `json:"status"`
+	// error message
+	ErrorMessage *string `json:"errorMessage,omitempty"`
+	// metadata
+	Metadata *RestoreUserOutputMetadata `json:"metadata,omitempty"`
+}
+
+type RestoreUserOutputMetadata struct {
+	// Restored User Information
+	User *User `json:"user,omitempty"`
+}
+
+// Subscription
+type Subscription struct {
+}
+
+// Update User Input
+// only specified fields are updated
+type UpdateUserInput struct {
+	// User ID (global ID or database ID)
+	ID string `json:"id"`
+	// User Name
+	Name *string `json:"name,omitempty"`
+	// Email Address
+	Email *string `json:"email,omitempty"`
+}
+
+// Update User Output
+type UpdateUserOutput struct {
+	// status
+	Status MutationStatus `json:"status"`
+	// error message
+	ErrorMessage *string `json:"errorMessage,omitempty"`
+	// path of the input field which caused the error (ex. "input.email")
+	ErrorField *string `json:"errorField,omitempty"`
+	// problems of input fields when status is VALIDATION_ERROR
+	Problems []*Problem `json:"problems"`
+	// metadata
+	Metadata *UpdateUserOutputMetadata `json:"metadata,omitempty"`
+}
+
+type UpdateUserOutputMetadata struct {
+	// Updated User Information
+	User *User `json:"user,omitempty"`
+}
+
 // User Information
 type User struct {
-	// ユーザID
+	// User ID (global ID)
 	ID string `json:"id"`
-	// 名前
+	// User ID in the database
+	DatabaseID string `json:"databaseId"`
+	// User Name
 	Name string `json:"name"`
-	// メールアドレス
-	Email string `json:"email"`
+	// Email Address (visible to the user themself or administrators)
+	Email *string `json:"email,omitempty"`
+	// Creation Date
+	CreatedAt time.Time `json:"createdAt"`
+	// Last Update Date
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (User) IsNode() {}
+
+// global ID
+func (this User) GetID() string { return this.ID }
+
+// Paginated Users
+type UserConnection struct {
+	// edges
+	Edges []*UserEdge `json:"edges"`
+	// pagination information
+	PageInfo *PageInfo `json:"pageInfo"`
+}
+
+// User in a connection
+type UserEdge struct {
+	// cursor of the user
+	Cursor string `json:"cursor"`
+	// User Information
+	Node *User `json:"node"`
+}
+
+// Order of Users
+type UserOrder struct {
+	// field to order by
+	Field UserOrderField `json:"field"`
+	// direction of the order
+	Direction OrderDirection `json:"direction"`
+}
+
+// Format of a string checked by @constraint
+type ConstraintFormat string
+
+const (
+	// email address (a subset of RFC 5322 addr-spec)
+	ConstraintFormatEmail ConstraintFormat = "EMAIL"
+	// UUID version 7
+	ConstraintFormatUUID ConstraintFormat = "UUID"
+)
+
+var AllConstraintFormat = []ConstraintFormat{
+	ConstraintFormatEmail,
+	ConstraintFormatUUID,
+}
+
+func (e ConstraintFormat) IsValid() bool {
+	switch e {
+	case ConstraintFormatEmail, ConstraintFormatUUID:
+		return true
+	}
+	return false
+}
+
+func (e ConstraintFormat) String() string {
+	return string(e)
+}
+
+func (e *ConstraintFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ConstraintFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ConstraintFormat", str)
+	}
+	return nil
+}
+
+func (e ConstraintFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
 // Mutationの処理結果
 type MutationStatus string
 
 const (
-	// 成功
+	// success
 	MutationStatusSuccess MutationStatus = "SUCCESS"
-	// 作成済み
+	// already exists
 	MutationStatusAlreadyExists MutationStatus = "ALREADY_EXISTS"
-	// 失敗
+	// not found
+	MutationStatusNotFound MutationStatus = "NOT_FOUND"
+	// failure
 	MutationStatusFailure MutationStatus = "FAILURE"
-	// バリデーションエラー
+	// validation error
 	MutationStatusValidationError MutationStatus = "VALIDATION_ERROR"
 )
 
 var AllMutationStatus = []MutationStatus{
 	MutationStatusSuccess,
 	MutationStatusAlreadyExists,
+	MutationStatusNotFound,
 	MutationStatusFailure,
 	MutationStatusValidationError,
 }
 
 func (e MutationStatus) IsValid() bool {
 	switch e {
-	case MutationStatusSuccess, MutationStatusAlreadyExists, MutationStatusFailure, MutationStatusValidationError:
+	case MutationStatusSuccess, MutationStatusAlreadyExists, MutationStatusNotFound, MutationStatusFailure, MutationStatusValidationError:
 		return true
 	}
 	return false
@@ -98,3 +292,185 @@ func (e *MutationStatus) UnmarshalGQL(v interface{}) error {
 func (e MutationStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+// Order Direction
+type OrderDirection string
+
+const (
+	// ascending
+	OrderDirectionAsc OrderDirection = "ASC"
+	// descending
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Kind of a Problem
+type ProblemCode string
+
+const (
+	// shorter than minLength
+	ProblemCodeTooShort ProblemCode = "TOO_SHORT"
+	// longer than maxLength
+	ProblemCodeTooLong ProblemCode = "TOO_LONG"
+	// does not match the pattern
+	ProblemCodePatternMismatch ProblemCode = "PATTERN_MISMATCH"
+	// does not match the format
+	ProblemCodeInvalidFormat ProblemCode = "INVALID_FORMAT"
+	// other invalid values
+	ProblemCodeInvalid ProblemCode = "INVALID"
+)
+
+var AllProblemCode = []ProblemCode{
+	ProblemCodeTooShort,
+	ProblemCodeTooLong,
+	ProblemCodePatternMismatch,
+	ProblemCodeInvalidFormat,
+	ProblemCodeInvalid,
+}
+
+func (e ProblemCode) IsValid() bool {
+	switch e {
+	case ProblemCodeTooShort, ProblemCodeTooLong, ProblemCodePatternMismatch, ProblemCodeInvalidFormat, ProblemCodeInvalid:
+		return true
+	}
+	return false
+}
+
+func (e ProblemCode) String() string {
+	return string(e)
+}
+
+func (e *ProblemCode) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProblemCode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProblemCode", str)
+	}
+	return nil
+}
+
+func (e ProblemCode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Role of an authenticated User
+type Role string
+
+const (
+	// ordinary user
+	RoleUser Role = "USER"
+	// administrator
+	RoleAdmin Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleUser,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleUser, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Fields to order Users by
+type UserOrderField string
+
+const (
+	// User ID (UUIDv7, ordered by creation time)
+	UserOrderFieldID UserOrderField = "ID"
+)
+
+var AllUserOrderField = []UserOrderField{
+	UserOrderFieldID,
+}
+
+func (e UserOrderField) IsValid() bool {
+	switch e {
+	case UserOrderFieldID:
+		return true
+	}
+	return false
+}
+
+func (e UserOrderField) String() string {
+	return string(e)
+}
+
+func (e *UserOrderField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserOrderField", str)
+	}
+	return nil
+}
+
+func (e UserOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
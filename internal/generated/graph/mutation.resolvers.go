@@ -10,6 +10,8 @@ import (
 	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/event"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
 )
 
@@ -18,23 +20,107 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input CreateUserInput
 	id, err := uuid.NewV7()
 	if err != nil {
 		msg := errors.Join(err, errors.New("failed to create user id")).Error()
-		slog.Error(msg, "email", input.Email, "name", input.Name)
+		slog.ErrorContext(ctx, msg, "email", input.Email, "name", input.Name)
 		return &CreateUserOutput{Status: MutationStatusFailure, ErrorMessage: &msg}, nil
 	}
 	result, err := r.DBClient.InsertUser(ctx, db.InsertUserParams{ID: id.String(), UserName: input.Name, Email: input.Email})
 	if err != nil {
-		msg := errors.Join(err, errors.New("failed to insert user")).Error()
-		slog.Error(msg, "email", input.Email, "name", input.Name)
-		return &CreateUserOutput{Status: MutationStatusFailure, ErrorMessage: &msg}, nil
+		appErr := apperr.FromDB(err, userConstraintFields)
+		if appErr.Code == apperr.CodeInternal {
+			msg := errors.Join(err, errors.New("failed to insert user")).Error()
+			slog.ErrorContext(ctx, msg, "email", input.Email, "name", input.Name)
+		}
+		return newCreateUserErrorOutput(appErr), nil
 	}
+	r.publishUser(ctx, event.UserCreated, result.ID)
 	return &CreateUserOutput{
 		Status: MutationStatusSuccess,
 		Metadata: &CreateUserOutputMetadata{
-			User: &User{
-				ID:    result.ID,
-				Name:  result.UserName,
-				Email: result.Email,
-			},
+			User: newUser(result),
+		},
+	}, nil
+}
+
+// UpdateUser is the resolver for the updateUser field.
+func (r *mutationResolver) UpdateUser(ctx context.Context, input UpdateUserInput) (*UpdateUserOutput, error) {
+	if err := validateUpdateUserInput(input); err != nil {
+		return newUpdateUserErrorOutput(err), nil
+	}
+	userID, appErr := decodeUserID("input.id", input.ID)
+	if appErr != nil {
+		return newUpdateUserErrorOutput(appErr), nil
+	}
+	if err := authorizeUser(ctx, "input.id", userID); err != nil {
+		return nil, err
+	}
+	result, err := r.DBClient.UpdateUser(ctx, db.UpdateUserParams{ID: userID, UserName: input.Name, Email: input.Email})
+	if err != nil {
+		appErr := apperr.FromDB(err, userConstraintFields)
+		if appErr.Code == apperr.CodeInternal {
+			msg := errors.Join(err, errors.New("failed to update user")).Error()
+			slog.ErrorContext(ctx, msg, "id", input.ID)
+		}
+		return newUpdateUserErrorOutput(appErr), nil
+	}
+	r.publishUser(ctx, event.UserUpdated, result.ID)
+	return &UpdateUserOutput{
+		Status: MutationStatusSuccess,
+		Metadata: &UpdateUserOutputMetadata{
+			User: newUser(result),
+		},
+	}, nil
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, input DeleteUserInput) (*DeleteUserOutput, error) {
+	userID, appErr := decodeUserID("input.id", input.ID)
+	if appErr != nil {
+		return newDeleteUserErrorOutput(appErr), nil
+	}
+	if err := authorizeUser(ctx, "input.id", userID); err != nil {
+		return nil, err
+	}
+	result, err := r.DBClient.SoftDeleteUser(ctx, userID)
+	if err != nil {
+		appErr := apperr.FromDB(err, userConstraintFields)
+		if appErr.Code == apperr.CodeInternal {
+			msg := errors.Join(err, errors.New("failed to delete user")).Error()
+			slog.ErrorContext(ctx, msg, "id", input.ID)
+		}
+		return newDeleteUserErrorOutput(appErr), nil
+	}
+	r.publishUser(ctx, event.UserUpdated, result.ID)
+	return &DeleteUserOutput{
+		Status: MutationStatusSuccess,
+		Metadata: &DeleteUserOutputMetadata{
+			User: newUser(result),
+		},
+	}, nil
+}
+
+// RestoreUser is the resolver for the restoreUser field.
+func (r *mutationResolver) RestoreUser(ctx context.Context, input RestoreUserInput) (*RestoreUserOutput, error) {
+	userID, appErr := decodeUserID("input.id", input.ID)
+	if appErr != nil {
+		return newRestoreUserErrorOutput(appErr), nil
+	}
+	if err := authorizeUser(ctx, "input.id", userID); err != nil {
+		return nil, err
+	}
+	result, err := r.DBClient.RestoreUser(ctx, userID)
+	if err != nil {
+		appErr := apperr.FromDB(err, userConstraintFields)
+		if appErr.Code == apperr.CodeInternal {
+			msg := errors.Join(err, errors.New("failed to restore user")).Error()
+			slog.ErrorContext(ctx, msg, "id", input.ID)
+		}
+		return newRestoreUserErrorOutput(appErr), nil
+	}
+	r.publishUser(ctx, event.UserUpdated, result.ID)
+	return &RestoreUserOutput{
+		Status: MutationStatusSuccess,
+		Metadata: &RestoreUserOutputMetadata{
+			User: newUser(result),
 		},
 	}, nil
 }
@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jackc/pgx/v5"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/globalid"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/loader"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// This file will not be regenerated automatically.
+//
+// It resolves objects which implement the Node interface from their global IDs.
+
+// global ID type names
+const (
+	nodeTypeUser = "User"
+)
+
+// nodeFetcher fetches an object by the key of its global ID
+// it returns a thunk so that objects of several IDs can be fetched in a batch
+// the thunk returns nil without error if the object does not exist
+type nodeFetcher func(ctx context.Context, r *Resolver, key string) func() (Node, error)
+
+// nodeFetchers is a registry of nodeFetcher
+// key: type name of global ID, value: fetcher of the type
+// add a fetcher here when a new type implements the Node interface
+var nodeFetchers = map[string]nodeFetcher{
+	nodeTypeUser: fetchUserNode,
+}
+
+// fetchNode resolves a global ID into a thunk of an object
+func (r *Resolver) fetchNode(ctx context.Context, id string) (func() (Node, error), error) {
+	gid, err := globalid.Decode(id)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeValidation, "id", "invalid global id")
+	}
+	fetch, ok := nodeFetchers[gid.Type]
+	if !ok {
+		return nil, apperr.New(apperr.CodeValidation, "id", fmt.Sprintf("unknown type: %s", gid.Type))
+	}
+	return fetch(ctx, r, gid.Key), nil
+}
+
+// addNodeError adds an error of the node at index to the response, the node is null
+func addNodeError(ctx context.Context, index int, err error) {
+	path := append(slices.Clone(graphql.GetPath(ctx)), ast.PathIndex(index))
+	graphql.AddError(ctx, gqlerror.WrapPath(path, err))
+}
+
+func fetchUserNode(ctx context.Context, _ *Resolver, key string) func() (Node, error) {
+	thunk := loader.FromContext(ctx).User.LoadThunk(ctx, key)
+	return func() (Node, error) {
+		u, err := thunk()
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user by id: %w", err)
+		}
+		return newUser(u), nil
+	}
+}
+
+// decodeUserID converts a user ID argument into the ID in the database
+// both global IDs and database IDs are accepted, and IDs are validated as the UUID scalar,
+// so the returned ID is canonical (ex. uppercase IDs are lowercased before the owner check)
+func decodeUserID(field string, id string) (string, *apperr.Error) {
+	if key, err := UnmarshalUUID(id); err == nil {
+		return key, nil
+	}
+	gid, err := globalid.Decode(id)
+	if err != nil || gid.Type != nodeTypeUser {
+		return "", apperr.New(apperr.CodeValidation, field, "invalid user id")
+	}
+	key, err := UnmarshalUUID(gid.Key)
+	if err != nil {
+		return "", apperr.New(apperr.CodeValidation, field, "invalid user id")
+	}
+	return key, nil
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/globalid"
+)
+
+func TestDecodeUserID(t *testing.T) {
+	const id = "01890a5d-ac96-774b-bcce-b302099a8057"
+
+	testCases := map[string]struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		"success: database_id": {
+			input: id,
+			want:  id,
+		},
+		"success: global_id": {
+			input: globalid.Encode(nodeTypeUser, id),
+			want:  id,
+		},
+		"success: uppercase_database_id_is_canonicalized": {
+			input: "01890A5D-AC96-774B-BCCE-B302099A8057",
+			want:  id,
+		},
+		"success: uppercase_key_of_global_id_is_canonicalized": {
+			input: globalid.Encode(nodeTypeUser, "01890A5D-AC96-774B-BCCE-B302099A8057"),
+			want:  id,
+		},
+		"failure: urn_database_id": {
+			input:   "urn:uuid:" + id,
+			wantErr: true,
+		},
+		"failure: braced_database_id": {
+			input:   "{" + id + "}",
+			wantErr: true,
+		},
+		"failure: other_type": {
+			input:   globalid.Encode("Post", id),
+			wantErr: true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := decodeUserID("id", tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected id: %v", diff)
+			}
+		})
+	}
+}
@@ -6,20 +6,122 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/loader"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/pagination"
 )
 
+// Viewer is the resolver for the viewer field.
+func (r *queryResolver) Viewer(ctx context.Context) (*User, error) {
+	// the principal is always set by @auth
+	u, err := r.DBClient.FindUserByID(ctx, db.FindUserByIDParams{ID: auth.FromContext(ctx).UserID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "", "user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user by id: %w", err)
+	}
+	return newUser(u), nil
+}
+
+// Node is the resolver for the node field.
+func (r *queryResolver) Node(ctx context.Context, id string) (Node, error) {
+	thunk, err := r.fetchNode(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return thunk()
+}
+
+// Nodes is the resolver for the nodes field.
+func (r *queryResolver) Nodes(ctx context.Context, ids []string) ([]Node, error) {
+	// fetch all nodes in a batch
+	// errors are reported for each ID, so that other nodes are returned
+	thunks := make([]func() (Node, error), len(ids))
+	for i, id := range ids {
+		thunk, err := r.fetchNode(ctx, id)
+		if err != nil {
+			addNodeError(ctx, i, err)
+			continue
+		}
+		thunks[i] = thunk
+	}
+	nodes := make([]Node, len(ids))
+	for i, thunk := range thunks {
+		if thunk == nil {
+			continue
+		}
+		n, err := thunk()
+		if err != nil {
+			addNodeError(ctx, i, err)
+			continue
+		}
+		nodes[i] = n
+	}
+	return nodes, nil
+}
+
 // User is the resolver for the user field.
-func (r *queryResolver) User(ctx context.Context, id string) (*User, error) {
-	u, err := r.DBClient.FindUserByID(ctx, id)
+func (r *queryResolver) User(ctx context.Context, id string, includeDeleted bool) (*User, error) {
+	if includeDeleted && !auth.FromContext(ctx).IsAdmin() {
+		return nil, apperr.New(apperr.CodeForbidden, "includeDeleted", "includeDeleted requires admin role")
+	}
+	var u db.User
+	var err error
+	if includeDeleted {
+		u, err = r.DBClient.FindUserByID(ctx, db.FindUserByIDParams{ID: id, IncludeDeleted: includeDeleted})
+	} else {
+		u, err = loader.FromContext(ctx).User.Load(ctx, id)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "id", "user not found")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user by id: %w", err)
 	}
-	return &User{
-		ID:    u.ID,
-		Name:  u.UserName,
-		Email: u.Email,
-	}, nil
+	return newUser(u), nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, first *int, after *string, last *int, before *string, orderBy *UserOrder, includeDeleted bool) (*UserConnection, error) {
+	if includeDeleted && !auth.FromContext(ctx).IsAdmin() {
+		return nil, apperr.New(apperr.CodeForbidden, "includeDeleted", "includeDeleted requires admin role")
+	}
+	page, err := pagination.NewPage(pagination.Args{First: first, After: after, Last: last, Before: before})
+	if err != nil {
+		return nil, err
+	}
+
+	ascending := orderBy == nil || orderBy.Direction == OrderDirectionAsc
+	lower, upper := page.Bounds(ascending)
+	var rows []db.User
+	if page.ScanAscending(ascending) {
+		rows, err = r.DBClient.ListUsersAscending(ctx, db.ListUsersAscendingParams{
+			LowerID:        lower,
+			UpperID:        upper,
+			IncludeDeleted: includeDeleted,
+			Limit:          page.FetchLimit(),
+		})
+	} else {
+		rows, err = r.DBClient.ListUsersDescending(ctx, db.ListUsersDescendingParams{
+			LowerID:        lower,
+			UpperID:        upper,
+			IncludeDeleted: includeDeleted,
+			Limit:          page.FetchLimit(),
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	rows, info := pagination.Trim(page, rows)
+	return newUserConnection(rows, info), nil
 }
 
 // Query returns QueryResolver implementation.
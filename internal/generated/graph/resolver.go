@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"github.com/rikeda71/go-gql-sqlc-template/internal/event"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
 )
@@ -12,4 +13,5 @@ import (
 type Resolver struct {
 	DBClient      *db.Queries
 	MetricsClient *metrics.Client
+	Broker        *event.Broker
 }
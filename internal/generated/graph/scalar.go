@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+)
+
+// This file will not be regenerated automatically.
+//
+// It defines marshalers of custom scalars.
+
+const (
+	// maxEmailLocalLength is the limit of the local part of RFC 5321
+	maxEmailLocalLength = 64
+	// maxEmailAddressLength is the limit of a forward path of RFC 5321
+	maxEmailAddressLength = 254
+)
+
+// emailPattern is a subset of RFC 5322 addr-spec
+// the local part is a dot-atom, and the domain is a hostname with at least two labels
+var emailPattern = regexp.MustCompile(
+	"^[a-z0-9!#$%&'*+/=?^_`{|}~-]+(\\.[a-z0-9!#$%&'*+/=?^_`{|}~-]+)*" +
+		"@[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)+$",
+)
+
+// MarshalDateTime marshals time.Time into an RFC 3339 string in UTC
+func MarshalDateTime(t time.Time) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(t.UTC().Format(time.RFC3339Nano)))
+	})
+}
+
+// UnmarshalDateTime unmarshals an RFC 3339 string into time.Time
+func UnmarshalDateTime(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, apperr.New(apperr.CodeValidation, "", fmt.Sprintf("DateTime must be a string, got %T", v))
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, apperr.Wrap(err, apperr.CodeValidation, "", "DateTime must be in RFC 3339 format")
+	}
+	return t, nil
+}
+
+// MarshalEmail marshals an email address
+func MarshalEmail(s string) graphql.Marshaler {
+	return graphql.MarshalString(s)
+}
+
+// UnmarshalEmail unmarshals an email address in lower case
+// addresses are compared case-insensitively by the unique index of lower(users.email)
+func UnmarshalEmail(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", apperr.New(apperr.CodeValidation, "", fmt.Sprintf("Email must be a string, got %T", v))
+	}
+	email := strings.ToLower(s)
+	at := strings.LastIndex(email, "@")
+	switch {
+	case len(email) > maxEmailAddressLength || at > maxEmailLocalLength:
+		return "", apperr.New(apperr.CodeValidation, "", "Email is too long")
+	case !emailPattern.MatchString(email):
+		return "", apperr.New(apperr.CodeValidation, "", "Email must be a valid email address")
+	}
+	return email, nil
+}
+
+// MarshalUUID marshals a UUID string
+func MarshalUUID(s string) graphql.Marshaler {
+	return graphql.MarshalString(s)
+}
+
+// UnmarshalUUID unmarshals a UUID v7 into the canonical lower case form
+// IDs of the database are generated by uuid.NewV7
+func UnmarshalUUID(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", apperr.New(apperr.CodeValidation, "", fmt.Sprintf("UUID must be a string, got %T", v))
+	}
+	id, err := uuid.Parse(s)
+	if err != nil || len(s) != len(id.String()) {
+		return "", apperr.New(apperr.CodeValidation, "", "UUID must be in the form of xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+	}
+	if id.Version() != 7 || id.Variant() != uuid.RFC4122 {
+		return "", apperr.New(apperr.CodeValidation, "", "UUID must be version 7")
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+)
+
+func TestMarshalDateTime(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	testCases := map[string]struct {
+		input time.Time
+		want  string
+	}{
+		"success: utc": {
+			input: time.Date(2024, 7, 23, 5, 4, 56, 0, time.UTC),
+			want:  `"2024-07-23T05:04:56Z"`,
+		},
+		"success: converted_to_utc": {
+			input: time.Date(2024, 7, 23, 14, 4, 56, 123456000, jst),
+			want:  `"2024-07-23T05:04:56.123456Z"`,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			MarshalDateTime(tt.input).MarshalGQL(&buf)
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("unexpected value: %v", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalDateTime(t *testing.T) {
+	testCases := map[string]struct {
+		input    interface{}
+		want     time.Time
+		wantCode apperr.Code
+	}{
+		"success: utc": {
+			input: "2024-07-23T05:04:56Z",
+			want:  time.Date(2024, 7, 23, 5, 4, 56, 0, time.UTC),
+		},
+		"success: offset": {
+			input: "2024-07-23T14:04:56.5+09:00",
+			want:  time.Date(2024, 7, 23, 5, 4, 56, 500000000, time.UTC),
+		},
+		"failure: no_timezone": {
+			input:    "2024-07-23T05:04:56",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: not_string": {
+			input:    1721711096,
+			wantCode: apperr.CodeValidation,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := UnmarshalDateTime(tt.input)
+			if (err != nil) != (tt.wantCode != "") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantCode, codeOf(err)); diff != "" {
+				t.Errorf("unexpected code: %v", diff)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("unexpected time: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalEmail(t *testing.T) {
+	testCases := map[string]struct {
+		input    interface{}
+		want     string
+		wantCode apperr.Code
+	}{
+		"success: lower_case": {
+			input: "user@example.com",
+			want:  "user@example.com",
+		},
+		"success: normalized_to_lower_case": {
+			input: "First.Last+Tag@Mail.Example.COM",
+			want:  "first.last+tag@mail.example.com",
+		},
+		"failure: no_at_sign": {
+			input:    "user.example.com",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: empty_local_part": {
+			input:    "@example.com",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: consecutive_dots": {
+			input:    "first..last@example.com",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: single_label_domain": {
+			input:    "user@localhost",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: hyphen_at_end_of_label": {
+			input:    "user@example-.com",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: too_long_local_part": {
+			input:    strings.Repeat("a", 65) + "@example.com",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: not_string": {
+			input:    1,
+			wantCode: apperr.CodeValidation,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := UnmarshalEmail(tt.input)
+			if (err != nil) != (tt.wantCode != "") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantCode, codeOf(err)); diff != "" {
+				t.Errorf("unexpected code: %v", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected email: %v", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalUUID(t *testing.T) {
+	testCases := map[string]struct {
+		input    interface{}
+		want     string
+		wantCode apperr.Code
+	}{
+		"success: v7": {
+			input: "01890a5d-ac96-774b-bcce-b302099a8057",
+			want:  "01890a5d-ac96-774b-bcce-b302099a8057",
+		},
+		"success: normalized_to_lower_case": {
+			input: "01890A5D-AC96-774B-BCCE-B302099A8057",
+			want:  "01890a5d-ac96-774b-bcce-b302099a8057",
+		},
+		"failure: v4": {
+			input:    "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: nil_uuid": {
+			input:    "00000000-0000-0000-0000-000000000000",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: without_hyphens": {
+			input:    "01890a5dac96774bbcceb302099a8057",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: urn": {
+			input:    "urn:uuid:01890a5d-ac96-774b-bcce-b302099a8057",
+			wantCode: apperr.CodeValidation,
+		},
+		"failure: invalid": {
+			input:    "invalid",
+			wantCode: apperr.CodeValidation,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := UnmarshalUUID(tt.input)
+			if (err != nil) != (tt.wantCode != "") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantCode, codeOf(err)); diff != "" {
+				t.Errorf("unexpected code: %v", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected uuid: %v", diff)
+			}
+		})
+	}
+}
+
+// codeOf returns the code of an error, empty if err is nil
+func codeOf(err error) apperr.Code {
+	if err == nil {
+		return ""
+	}
+	return apperr.CodeOf(err)
+}
@@ -0,0 +1,30 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.55
+
+import (
+	"context"
+
+	"github.com/rikeda71/go-gql-sqlc-template/internal/event"
+)
+
+// UserCreated is the resolver for the userCreated field.
+func (r *subscriptionResolver) UserCreated(ctx context.Context) (<-chan *User, error) {
+	return r.streamUsers(ctx, r.Broker.Subscribe(ctx, event.UserCreated), false), nil
+}
+
+// UserUpdated is the resolver for the userUpdated field.
+func (r *subscriptionResolver) UserUpdated(ctx context.Context, id string) (<-chan *User, error) {
+	userID, appErr := decodeUserID("id", id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return r.streamUsers(ctx, filterUser(ctx, r.Broker.Subscribe(ctx, event.UserUpdated), userID), true), nil
+}
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,18 @@
+package graph
+
+import (
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+)
+
+// This file will not be regenerated automatically.
+//
+// It validates inputs which @constraint cannot express.
+
+// validateUpdateUserInput validates UpdateUserInput
+// limits of each field are validated by @constraint
+func validateUpdateUserInput(input UpdateUserInput) *apperr.Error {
+	if input.Name == nil && input.Email == nil {
+		return apperr.New(apperr.CodeValidation, "input", "either name or email must be specified")
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package globalid
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// separator separates the type name and the key in a global ID
+const separator = ":"
+
+// ErrInvalid is returned when a string is not a global ID
+var ErrInvalid = errors.New("invalid global id")
+
+// ID is a decoded global object identifier
+// https://relay.dev/graphql/objectidentification.htm
+type ID struct {
+	// Type is a GraphQL type name (ex. "User")
+	Type string
+	// Key is an identifier of the object in its type (ex. UUID of the user)
+	Key string
+}
+
+// Encode encodes a type name and a key into an opaque global ID
+func Encode(typ string, key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(typ + separator + key))
+}
+
+// Decode decodes an opaque global ID
+func Decode(s string) (ID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ID{}, ErrInvalid
+	}
+	typ, key, ok := strings.Cut(string(b), separator)
+	if !ok || typ == "" || key == "" {
+		return ID{}, ErrInvalid
+	}
+	return ID{Type: typ, Key: key}, nil
+}
+
+// String encodes the ID
+func (id ID) String() string {
+	return Encode(id.Type, id.Key)
+}
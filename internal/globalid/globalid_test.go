@@ -0,0 +1,50 @@
+package globalid
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecode(t *testing.T) {
+	testCases := map[string]struct {
+		input   string
+		want    ID
+		wantErr bool
+	}{
+		"success: user": {
+			input: Encode("User", "01920000-0000-7000-8000-000000000000"),
+			want:  ID{Type: "User", Key: "01920000-0000-7000-8000-000000000000"},
+		},
+		"failure: raw_uuid": {
+			input:   "01920000-0000-7000-8000-000000000000",
+			wantErr: true,
+		},
+		"failure: no_separator": {
+			input:   Encode("User", "")[:4],
+			wantErr: true,
+		},
+		"failure: empty_key": {
+			input:   Encode("User", ""),
+			wantErr: true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Decode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected id: %v", diff)
+			}
+			if err == nil && got.String() != tt.input {
+				t.Errorf("want %v, but got %v", tt.input, got.String())
+			}
+		})
+	}
+}
@@ -1,21 +1,166 @@
 package internal
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/cachecontrol"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/event"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/introspection"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/loader"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/persisted"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/querylimit"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/requestid"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/tracing"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.opentelemetry.io/otel"
 )
 
-func NewGraphQLHandler(cnf *Config, dbc *db.Queries, m *metrics.Client) (*handler.Server, error) {
+func NewGraphQLHandler(cnf *Config, dbc *db.Queries, broker *event.Broker, verifier *auth.Verifier, m *metrics.Client) (*handler.Server, error) {
 	// initialize usecase, service, or repository through selected architecture
 
-	gqlHandler := *handler.NewDefaultServer(
-		graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
-			DBClient:      dbc,
-			MetricsClient: m,
-		}}),
+	gqlHandler := *handler.New(
+		graph.NewExecutableSchema(graph.Config{
+			Resolvers: &graph.Resolver{
+				DBClient:      dbc,
+				MetricsClient: m,
+				Broker:        broker,
+			},
+			Directives: graph.DirectiveRoot{
+				Auth:       graph.AuthDirective,
+				Owner:      graph.OwnerDirective,
+				Constraint: graph.ConstraintDirective,
+			},
+			Complexity: graph.NewComplexityRoot(),
+		}),
 	)
+	gqlHandler.AddTransport(transport.Websocket{
+		InitFunc:              authenticateInit(verifier),
+		InitTimeout:           cnf.WebSocketInitTimeout,
+		KeepAlivePingInterval: cnf.WebSocketKeepAlive,
+		PingPongInterval:      cnf.WebSocketPingInterval,
+	})
+	gqlHandler.AddTransport(transport.Options{})
+	gqlHandler.AddTransport(transport.GET{})
+	gqlHandler.AddTransport(transport.POST{})
+	gqlHandler.AddTransport(transport.MultipartForm{})
+	gqlHandler.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	// introspection is controlled per environment
+	introspectionExt, err := introspection.NewExtension(cnf.IntrospectionMode())
+	if err != nil {
+		return nil, err
+	}
+	gqlHandler.Use(introspectionExt)
+	// spans of operations enclose other extensions
+	gqlHandler.Use(tracing.NewExtension(otel.GetTracerProvider()))
+	gqlHandler.Use(requestid.Extension{})
+	gqlHandler.Use(cachecontrol.Extension{})
+	// persisted queries are resolved before parsing
+	pq, err := persisted.NewExtension(cnf.PersistedQueryConfig(), dbc)
+	if err != nil {
+		return nil, err
+	}
+	gqlHandler.Use(pq)
+	// reject expensive operations before they are executed
+	querylimit.RegisterMetrics(m)
+	gqlHandler.Use(querylimit.New(querylimit.Config{
+		MaxDepth:           cnf.GraphQLMaxDepth,
+		MaxComplexity:      cnf.GraphQLMaxComplexity,
+		AllowIntrospection: cnf.IntrospectionMode() != introspection.ModeDisabled,
+	}, m))
+	// request-scoped dataloaders
+	loader.RegisterMetrics(m)
+	loaderCnf := loader.Config{Wait: cnf.DataLoaderWait, MaxBatch: cnf.DataLoaderMaxBatch}
+	gqlHandler.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(loader.NewContext(ctx, loader.New(dbc, m, loaderCnf)))
+	})
+	// violations of @constraint are collected while parsing arguments and returned before resolvers run
+	gqlHandler.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(graph.NewConstraintContext(ctx))
+	})
+	gqlHandler.AroundFields(graph.ConstraintMiddleware)
+	gqlHandler.SetErrorPresenter(presentError)
+	gqlHandler.SetRecoverFunc(recoverPanic)
 
 	return &gqlHandler, nil
 }
+
+// authenticateInit verifies a bearer token in the payload of connection_init
+// the principal of the upgrade request is kept when the payload has no token
+func authenticateInit(v *auth.Verifier) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		header := payload.Authorization()
+		if header == "" {
+			return ctx, &payload, nil
+		}
+		token, ok := bearerToken(header)
+		if !ok {
+			return ctx, nil, errors.New("authorization must be a bearer token")
+		}
+		p, err := v.Verify(token)
+		if err != nil {
+			return ctx, nil, errors.New("invalid token")
+		}
+		return auth.NewContext(ctx, p), &payload, nil
+	}
+}
+
+// presentError maps errors into GraphQL errors with stable codes in `extensions.code`
+// messages of unclassified errors are hidden from clients because they may contain SQL
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	appErr, ok := apperr.As(err)
+	switch {
+	case ok && appErr.Code != apperr.CodeInternal:
+		extensions := map[string]interface{}{"code": appErr.Code}
+		if field := errorField(appErr, gqlErr.Path); field != "" {
+			extensions["field"] = field
+		}
+		return &gqlerror.Error{
+			Message:    appErr.Message,
+			Path:       gqlErr.Path,
+			Locations:  gqlErr.Locations,
+			Extensions: extensions,
+		}
+	case !ok && (gqlErr.Err == nil || gqlErr.Extensions["code"] != nil):
+		// errors of gqlgen itself (ex. parse, validation) are safe to show
+		return gqlErr
+	}
+
+	slog.ErrorContext(ctx, "internal error", "error", err.Error(), "path", gqlErr.Path.String())
+	return &gqlerror.Error{
+		Message:    "internal server error",
+		Path:       gqlErr.Path,
+		Locations:  gqlErr.Locations,
+		Extensions: map[string]interface{}{"code": apperr.CodeInternal},
+	}
+}
+
+// errorField returns the input field which caused the error
+// errors of scalars have no field, so it is derived from the path of arguments (ex. createUser.input.email -> input.email)
+func errorField(appErr *apperr.Error, path ast.Path) string {
+	if appErr.Field != "" || appErr.Code != apperr.CodeValidation || len(path) < 2 {
+		return appErr.Field
+	}
+	return path[1:].String()
+}
+
+// recoverPanic converts a panic in resolvers into an internal error
+func recoverPanic(ctx context.Context, p interface{}) error {
+	slog.ErrorContext(ctx, "panic in resolver", "panic", p, "stack", string(debug.Stack()))
+	return apperr.New(apperr.CodeInternal, "", fmt.Sprintf("panic: %v", p))
+}
@@ -0,0 +1,233 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestPresentError(t *testing.T) {
+	type expectedError struct {
+		Message    string
+		Extensions map[string]interface{}
+	}
+
+	testCases := map[string]struct {
+		err  error
+		want expectedError
+	}{
+		"success: not_found": {
+			err: fmt.Errorf("wrapped: %w", apperr.New(apperr.CodeNotFound, "id", "user not found")),
+			want: expectedError{
+				Message:    "user not found",
+				Extensions: map[string]interface{}{"code": apperr.CodeNotFound, "field": "id"},
+			},
+		},
+		"success: forbidden_without_field": {
+			err: apperr.New(apperr.CodeForbidden, "", "forbidden"),
+			want: expectedError{
+				Message:    "forbidden",
+				Extensions: map[string]interface{}{"code": apperr.CodeForbidden},
+			},
+		},
+		"success: field_of_scalar_error_from_path": {
+			err: &gqlerror.Error{
+				Err:  apperr.New(apperr.CodeValidation, "", "Email must be a valid email address"),
+				Path: ast.Path{ast.PathName("createUser"), ast.PathName("input"), ast.PathName("email")},
+			},
+			want: expectedError{
+				Message:    "Email must be a valid email address",
+				Extensions: map[string]interface{}{"code": apperr.CodeValidation, "field": "input.email"},
+			},
+		},
+		"success: hide_sql_error": {
+			err: errors.New(`failed to find user by id: ERROR: relation "users" does not exist (SQLSTATE 42P01)`),
+			want: expectedError{
+				Message:    "internal server error",
+				Extensions: map[string]interface{}{"code": apperr.CodeInternal},
+			},
+		},
+		"success: hide_internal_app_error": {
+			err: apperr.New(apperr.CodeInternal, "", "panic: runtime error"),
+			want: expectedError{
+				Message:    "internal server error",
+				Extensions: map[string]interface{}{"code": apperr.CodeInternal},
+			},
+		},
+		"success: keep_gqlgen_error": {
+			err: gqlerror.Errorf("Cannot query field \"foo\" on type \"Query\"."),
+			want: expectedError{
+				Message: "Cannot query field \"foo\" on type \"Query\".",
+			},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got := presentError(context.Background(), tt.err)
+			if diff := cmp.Diff(tt.want, expectedError{Message: got.Message, Extensions: got.Extensions}); diff != "" {
+				t.Errorf("unexpected error: %v", diff)
+			}
+		})
+	}
+}
+
+// introspectionQuery is the introspection query sent by GraphiQL (getIntrospectionQuery of graphql-js)
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      ...FullType
+    }
+    directives {
+      name
+      description
+      locations
+      args {
+        ...InputValue
+      }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args {
+      ...InputValue
+    }
+    type {
+      ...TypeRef
+    }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields {
+    ...InputValue
+  }
+  interfaces {
+    ...TypeRef
+  }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes {
+    ...TypeRef
+  }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+func TestIntrospectionQuery(t *testing.T) {
+	// the playground of debug mode loads the schema with the introspection query under the default limits
+	t.Setenv("DATABASE_USER", "user")
+	t.Setenv("DATABASE_PASSWORD", "password")
+	t.Setenv("DATABASE_HOST", "localhost")
+	t.Setenv("DATABASE_NAME", "db")
+	t.Setenv("DEBUG_MODE", "true")
+	t.Setenv("GRAPHQL_INTROSPECTION", "")
+	t.Setenv("PLAYGROUND", "")
+	cnf, err := NewConfig()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	m := metrics.NewClientWithRegistry(prometheus.NewRegistry(), prometheus.NewRegistry())
+	h, err := NewGraphQLHandler(cnf, db.New(nil), nil, nil, m)
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]string{"query": introspectionQuery})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var res struct {
+		Data struct {
+			Schema struct {
+				QueryType struct {
+					Name string `json:"name"`
+				} `json:"queryType"`
+			} `json:"__schema"`
+		} `json:"data"`
+		Errors []gqlerror.Error `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(res.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", res.Errors)
+	}
+	if diff := cmp.Diff("Query", res.Data.Schema.QueryType.Name); diff != "" {
+		t.Errorf("unexpected query type: %v", diff)
+	}
+}
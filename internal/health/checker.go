@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+)
+
+// PingChecker checks the connection to the database
+type PingChecker struct {
+	pool *pgxpool.Pool
+}
+
+// NewPingChecker is a constructor for PingChecker
+func NewPingChecker(pool *pgxpool.Pool) *PingChecker {
+	return &PingChecker{pool: pool}
+}
+
+func (c *PingChecker) Name() string {
+	return "database"
+}
+
+func (c *PingChecker) Check(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
+
+// MigrationChecker checks whether migrations which the server requires are applied
+type MigrationChecker struct {
+	q               *db.Queries
+	expectedVersion string
+}
+
+// NewMigrationChecker is a constructor for MigrationChecker
+// expectedVersion is the version of the latest migration the server requires
+func NewMigrationChecker(q *db.Queries, expectedVersion string) *MigrationChecker {
+	return &MigrationChecker{q: q, expectedVersion: expectedVersion}
+}
+
+func (c *MigrationChecker) Name() string {
+	return "migration"
+}
+
+func (c *MigrationChecker) Check(ctx context.Context) error {
+	version, err := c.q.FindLatestMigrationVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find migration version: %w", err)
+	}
+	// newer versions are allowed because migrations are applied before deployment
+	if version < c.expectedVersion {
+		return fmt.Errorf("migration version %s is older than %s", version, c.expectedVersion)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// checkTimeout is the timeout of all checks of a readiness probe
+const checkTimeout = 3 * time.Second
+
+// Status is a status of the server or a dependency
+type Status string
+
+const (
+	StatusUp   Status = "UP"
+	StatusDown Status = "DOWN"
+)
+
+// Checker checks whether a dependency is available
+type Checker interface {
+	// Name is a name of the dependency shown in the response
+	Name() string
+	// Check returns an error if the dependency is not available
+	Check(ctx context.Context) error
+}
+
+// DependencyStatus is a result of a Checker
+type DependencyStatus struct {
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Response is a response of health endpoints
+type Response struct {
+	Status       Status                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
+
+// Handler serves liveness and readiness probes
+type Handler struct {
+	checkers     []Checker
+	shuttingDown atomic.Bool
+}
+
+// NewHandler is a constructor for Handler
+func NewHandler(checkers ...Checker) *Handler {
+	return &Handler{checkers: checkers}
+}
+
+// Live reports that the process is running
+func (h *Handler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, Response{Status: StatusUp})
+}
+
+// Ready reports whether the server can accept requests
+// it is not ready when a dependency is not available or graceful shutdown has begun
+func (h *Handler) Ready(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), checkTimeout)
+	defer cancel()
+
+	res := Response{
+		Status:       StatusUp,
+		Dependencies: make(map[string]DependencyStatus, len(h.checkers)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range h.checkers {
+		wg.Add(1)
+		go func(checker Checker) {
+			defer wg.Done()
+			status := DependencyStatus{Status: StatusUp}
+			if err := checker.Check(ctx); err != nil {
+				status = DependencyStatus{Status: StatusDown, Error: err.Error()}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			res.Dependencies[checker.Name()] = status
+			if status.Status == StatusDown {
+				res.Status = StatusDown
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	if h.shuttingDown.Load() {
+		res.Status = StatusDown
+	}
+	if res.Status == StatusDown {
+		return c.JSON(http.StatusServiceUnavailable, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Shutdown marks the server as not ready
+// it is called when graceful shutdown begins so that load balancers stop sending requests
+func (h *Handler) Shutdown() {
+	h.shuttingDown.Store(true)
+}
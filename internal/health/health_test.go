@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+type fakeChecker struct {
+	name string
+	err  error
+}
+
+func (c fakeChecker) Name() string {
+	return c.name
+}
+
+func (c fakeChecker) Check(_ context.Context) error {
+	return c.err
+}
+
+func TestReady(t *testing.T) {
+	testCases := map[string]struct {
+		checkers     []Checker
+		shuttingDown bool
+		wantCode     int
+		want         Response
+	}{
+		"success: all_up": {
+			checkers: []Checker{fakeChecker{name: "database"}, fakeChecker{name: "migration"}},
+			wantCode: http.StatusOK,
+			want: Response{
+				Status: StatusUp,
+				Dependencies: map[string]DependencyStatus{
+					"database":  {Status: StatusUp},
+					"migration": {Status: StatusUp},
+				},
+			},
+		},
+		"failure: dependency_down": {
+			checkers: []Checker{fakeChecker{name: "database"}, fakeChecker{name: "migration", err: errors.New("old")}},
+			wantCode: http.StatusServiceUnavailable,
+			want: Response{
+				Status: StatusDown,
+				Dependencies: map[string]DependencyStatus{
+					"database":  {Status: StatusUp},
+					"migration": {Status: StatusDown, Error: "old"},
+				},
+			},
+		},
+		"failure: shutting_down": {
+			checkers:     []Checker{fakeChecker{name: "database"}},
+			shuttingDown: true,
+			wantCode:     http.StatusServiceUnavailable,
+			want: Response{
+				Status: StatusDown,
+				Dependencies: map[string]DependencyStatus{
+					"database": {Status: StatusUp},
+				},
+			},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(tt.checkers...)
+			if tt.shuttingDown {
+				h.Shutdown()
+			}
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/health/ready", nil), rec)
+			if err := h.Ready(c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantCode, rec.Code); diff != "" {
+				t.Errorf("unexpected status code: %v", diff)
+			}
+			var got Response
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected response: %v", diff)
+			}
+		})
+	}
+}
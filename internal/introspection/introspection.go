@@ -0,0 +1,95 @@
+package introspection
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// ModeEnabled allows introspection for everyone
+	ModeEnabled = "enabled"
+	// ModeDisabled rejects introspection, which is recommended in production
+	ModeDisabled = "disabled"
+	// ModeAdmin allows introspection only for admin principals
+	ModeAdmin = "admin"
+
+	// CodeDisabled is the error code of introspection operations which are not allowed
+	CodeDisabled = "INTROSPECTION_DISABLED"
+)
+
+// NewExtension returns a gqlgen extension of the configured mode
+func NewExtension(mode string) (graphql.HandlerExtension, error) {
+	switch mode {
+	case ModeEnabled:
+		return extension.Introspection{}, nil
+	case ModeDisabled:
+		return Guard{}, nil
+	case ModeAdmin:
+		return Guard{AllowAdmin: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown introspection mode: %q", mode)
+	}
+}
+
+// Guard is a gqlgen extension rejecting introspection operations
+// __typename is not introspection of the schema, so it is always allowed
+type Guard struct {
+	// AllowAdmin allows introspection for admin principals
+	AllowAdmin bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = Guard{}
+
+func (Guard) ExtensionName() string {
+	return "IntrospectionGuard"
+}
+
+func (Guard) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext rejects the operation before execution, so that clients receive a stable code
+func (g Guard) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if g.AllowAdmin && auth.FromContext(ctx).IsAdmin() {
+		rc.DisableIntrospection = false
+		return nil
+	}
+	rc.DisableIntrospection = true
+	if rc.Operation != nil && selectsIntrospection(rc.Operation.SelectionSet) {
+		return &gqlerror.Error{
+			Message:    "introspection is disabled",
+			Extensions: map[string]interface{}{"code": CodeDisabled},
+		}
+	}
+	return nil
+}
+
+// selectsIntrospection reports whether root fields of the selection set contain __schema or __type
+func selectsIntrospection(selectionSet ast.SelectionSet) bool {
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name == "__schema" || s.Name == "__type" {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectsIntrospection(s.SelectionSet) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			if s.Definition != nil && selectsIntrospection(s.Definition.SelectionSet) {
+				return true
+			}
+		}
+	}
+	return false
+}
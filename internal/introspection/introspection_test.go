@@ -0,0 +1,152 @@
+package introspection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	"github.com/vektah/gqlparser/v2"
+)
+
+func newOperationContext(t *testing.T, query string) *graphql.OperationContext {
+	t.Helper()
+	es := graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}})
+	doc, errs := gqlparser.LoadQuery(es.Schema(), query)
+	if errs != nil {
+		t.Fatalf("failed to parse query: %v", errs)
+	}
+	return &graphql.OperationContext{
+		RawQuery:  query,
+		Doc:       doc,
+		Operation: doc.Operations[0],
+	}
+}
+
+func TestGuard(t *testing.T) {
+	const schemaQuery = `query { __schema { queryType { name } } }`
+	admin := &auth.Principal{UserID: "admin", Role: auth.RoleAdmin}
+	user := &auth.Principal{UserID: "user", Role: auth.RoleUser}
+
+	testCases := map[string]struct {
+		guard     Guard
+		principal *auth.Principal
+		query     string
+		wantCode  string
+		wantAllow bool
+	}{
+		"success: typename": {
+			guard: Guard{},
+			query: `query { __typename }`,
+		},
+		"success: not_introspection": {
+			guard: Guard{},
+			query: `query { users { edges { node { id } } } }`,
+		},
+		"success: admin": {
+			guard:     Guard{AllowAdmin: true},
+			principal: admin,
+			query:     schemaQuery,
+			wantAllow: true,
+		},
+		"failure: disabled_for_admin": {
+			guard:     Guard{},
+			principal: admin,
+			query:     schemaQuery,
+			wantCode:  CodeDisabled,
+		},
+		"failure: user": {
+			guard:     Guard{AllowAdmin: true},
+			principal: user,
+			query:     schemaQuery,
+			wantCode:  CodeDisabled,
+		},
+		"failure: anonymous": {
+			guard:    Guard{AllowAdmin: true},
+			query:    `query { __type(name: "User") { name } }`,
+			wantCode: CodeDisabled,
+		},
+		"failure: in_fragment": {
+			guard: Guard{},
+			query: `
+			query { ...Schema }
+			fragment Schema on Query { __schema { queryType { name } } }
+			`,
+			wantCode: CodeDisabled,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.NewContext(ctx, tt.principal)
+			}
+			rc := newOperationContext(t, tt.query)
+			err := tt.guard.MutateOperationContext(ctx, rc)
+			if tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("error should be returned")
+				}
+				if diff := cmp.Diff(tt.wantCode, err.Extensions["code"]); diff != "" {
+					t.Errorf("unexpected code: %v", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(!tt.wantAllow, rc.DisableIntrospection); diff != "" {
+				t.Errorf("unexpected DisableIntrospection: %v", diff)
+			}
+		})
+	}
+}
+
+func TestNewExtension(t *testing.T) {
+	testCases := map[string]struct {
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		"success: enabled": {
+			mode: ModeEnabled,
+			want: "Introspection",
+		},
+		"success: disabled": {
+			mode: ModeDisabled,
+			want: "IntrospectionGuard",
+		},
+		"success: admin": {
+			mode: ModeAdmin,
+			want: "IntrospectionGuard",
+		},
+		"failure: unknown_mode": {
+			mode:    "public",
+			wantErr: true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewExtension(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got.ExtensionName()); diff != "" {
+				t.Errorf("unexpected extension: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package loader
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+	"github.com/vikstrous/dataloadgen"
+)
+
+const (
+	// BatchSizeHistogram is a histogram of the number of keys in a batch
+	BatchSizeHistogram = "dataloader_batch_size"
+
+	userLoader = "user"
+)
+
+// Config is the configuration of loaders
+type Config struct {
+	// Wait is the duration to wait for keys before fetching a batch
+	Wait time.Duration
+	// MaxBatch is the maximum number of keys in a batch (0 is unlimited)
+	MaxBatch int
+}
+
+// Store is the storage of loaders
+// it is implemented by db.Queries
+type Store interface {
+	FindUsersByIDs(ctx context.Context, ids []string) ([]db.User, error)
+}
+
+// Loaders is a set of request-scoped dataloaders
+// values are cached until the request is finished
+type Loaders struct {
+	// User loads users by ID, soft-deleted users are not found
+	User *dataloadgen.Loader[string, db.User]
+}
+
+// RegisterMetrics registers metrics of loaders
+// it must be called before loaders are created
+func RegisterMetrics(m *metrics.Client) {
+	batchSizeHistogram(m)
+}
+
+// batchLabels is labels of BatchSizeHistogram
+type batchLabels struct {
+	Loader string `label:"loader"`
+}
+
+// batchSizeHistogram returns the handle of BatchSizeHistogram
+func batchSizeHistogram(m *metrics.Client) *metrics.Histogram[batchLabels] {
+	return metrics.NewHistogram[batchLabels](m, BatchSizeHistogram, "DataLoaderのバッチサイズ", []float64{1, 2, 5, 10, 20, 50, 100, 200})
+}
+
+// New is a constructor for Loaders
+func New(store Store, m *metrics.Client, cnf Config) *Loaders {
+	opts := []dataloadgen.Option{
+		dataloadgen.WithWait(cnf.Wait),
+		dataloadgen.WithBatchCapacity(cnf.MaxBatch),
+	}
+	return &Loaders{
+		User: dataloadgen.NewLoader(fetchUsers(store, batchSizeHistogram(m)), opts...),
+	}
+}
+
+// fetchUsers returns a batch function of users
+// pgx.ErrNoRows is returned for IDs which are not found
+func fetchUsers(store Store, batchSize *metrics.Histogram[batchLabels]) func(ctx context.Context, ids []string) ([]db.User, []error) {
+	return func(ctx context.Context, ids []string) ([]db.User, []error) {
+		batchSize.Observe(batchLabels{Loader: userLoader}, float64(len(ids)))
+
+		users := make([]db.User, len(ids))
+		errs := make([]error, len(ids))
+		rows, err := store.FindUsersByIDs(ctx, ids)
+		if err != nil {
+			for i := range errs {
+				errs[i] = err
+			}
+			return users, errs
+		}
+
+		byID := make(map[string]db.User, len(rows))
+		for _, u := range rows {
+			byID[u.ID] = u
+		}
+		for i, id := range ids {
+			u, ok := byID[id]
+			if !ok {
+				errs[i] = pgx.ErrNoRows
+				continue
+			}
+			users[i] = u
+		}
+		return users, errs
+	}
+}
+
+type loadersKey struct{}
+
+// NewContext returns a new context which carries loaders
+func NewContext(ctx context.Context, l *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// FromContext returns loaders stored in ctx
+// it panics if loaders are not stored because the middleware is required
+func FromContext(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
@@ -0,0 +1,137 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+)
+
+// fakeStore returns users which are not soft-deleted in reverse order as the database does not keep the order
+type fakeStore struct {
+	users map[string]db.User
+	err   error
+
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (s *fakeStore) FindUsersByIDs(_ context.Context, ids []string) ([]db.User, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, slices.Clone(ids))
+	s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	var users []db.User
+	for _, id := range slices.Backward(ids) {
+		if u, ok := s.users[id]; ok && u.DeletedAt == nil {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func TestUserLoader(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := map[string]db.User{
+		"1": {ID: "1", UserName: "user1"},
+		"2": {ID: "2", UserName: "user2"},
+		"3": {ID: "3", UserName: "deleted", DeletedAt: &deletedAt},
+	}
+	dbErr := errors.New("connection refused")
+
+	testCases := map[string]struct {
+		ids       []string
+		err       error
+		want      []db.User
+		wantErrs  []error
+		wantCalls [][]string
+	}{
+		"success: keys_are_fetched_in_a_batch": {
+			ids:       []string{"1", "2"},
+			want:      []db.User{users["1"], users["2"]},
+			wantErrs:  []error{nil, nil},
+			wantCalls: [][]string{{"1", "2"}},
+		},
+		"success: results_are_in_key_order": {
+			ids:       []string{"2", "1"},
+			want:      []db.User{users["2"], users["1"]},
+			wantErrs:  []error{nil, nil},
+			wantCalls: [][]string{{"2", "1"}},
+		},
+		"success: missing_and_deleted_users_are_not_found": {
+			ids:       []string{"1", "404", "3"},
+			want:      []db.User{users["1"], {}, {}},
+			wantErrs:  []error{nil, pgx.ErrNoRows, pgx.ErrNoRows},
+			wantCalls: [][]string{{"1", "404", "3"}},
+		},
+		"failure: database_error": {
+			ids:       []string{"1", "2"},
+			err:       dbErr,
+			want:      []db.User{{}, {}},
+			wantErrs:  []error{dbErr, dbErr},
+			wantCalls: [][]string{{"1", "2"}},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			registry := prometheus.NewRegistry()
+			m := metrics.NewClientWithRegistry(registry, registry)
+			store := &fakeStore{users: users, err: tt.err}
+			loaders := New(store, m, Config{Wait: 10 * time.Millisecond})
+
+			ctx := context.Background()
+			thunks := make([]func() (db.User, error), len(tt.ids))
+			for i, id := range tt.ids {
+				thunks[i] = loaders.User.LoadThunk(ctx, id)
+			}
+			got := make([]db.User, len(tt.ids))
+			gotErrs := make([]error, len(tt.ids))
+			for i, thunk := range thunks {
+				got[i], gotErrs[i] = thunk()
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected users: %v", diff)
+			}
+			for i := range tt.wantErrs {
+				if !errors.Is(gotErrs[i], tt.wantErrs[i]) {
+					t.Errorf("unexpected error at %d: %v", i, gotErrs[i])
+				}
+			}
+			if diff := cmp.Diff(tt.wantCalls, store.calls); diff != "" {
+				t.Errorf("unexpected calls: %v", diff)
+			}
+
+			// the batch size is observed
+			families, err := registry.Gather()
+			if err != nil {
+				t.Fatalf("failed to gather metrics: %v", err)
+			}
+			var observed []float64
+			for _, f := range families {
+				if f.GetName() != BatchSizeHistogram {
+					continue
+				}
+				h := f.GetMetric()[0].GetHistogram()
+				observed = append(observed, float64(h.GetSampleCount()), h.GetSampleSum())
+			}
+			if diff := cmp.Diff([]float64{1, float64(len(tt.ids))}, observed); diff != "" {
+				t.Errorf("unexpected batch size: %v", diff)
+			}
+		})
+	}
+}
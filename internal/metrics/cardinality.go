@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// LabelSetsCollapsedTotal is a counter of label sets collapsed by label policies
+	LabelSetsCollapsedTotal = "metrics_label_sets_collapsed_total"
+
+	// OtherValue is the label value which overflowing or not allowed values are collapsed into
+	OtherValue = "__other__"
+
+	// ReasonLimit is the reason of label sets exceeding MaxLabelSets
+	ReasonLimit = "limit"
+	// ReasonNotAllowed is the reason of label values not in AllowedValues
+	ReasonNotAllowed = "not_allowed"
+)
+
+// LabelPolicy is a policy limiting label values of a metric
+// it keeps the number of series bounded even if callers pass unbounded values (ex. user IDs)
+type LabelPolicy struct {
+	// MaxLabelSets is the maximum number of label sets, all values of new label sets are collapsed into OtherValue when exceeded (0 is unlimited)
+	// the collapsed label set is not counted, so there are at most MaxLabelSets+1 series
+	MaxLabelSets int
+	// AllowedValues is allowed values of each label, other values are collapsed into OtherValue
+	// labels not in it accept any value
+	AllowedValues map[string][]string
+}
+
+// labelGuard applies a LabelPolicy to label values
+type labelGuard struct {
+	maxLabelSets int
+	allowed      map[string]map[string]struct{}
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func newLabelGuard(p LabelPolicy) *labelGuard {
+	g := &labelGuard{
+		maxLabelSets: p.MaxLabelSets,
+		allowed:      make(map[string]map[string]struct{}, len(p.AllowedValues)),
+		seen:         make(map[string]struct{}),
+	}
+	for label, values := range p.AllowedValues {
+		g.allowed[label] = make(map[string]struct{}, len(values))
+		for _, v := range values {
+			g.allowed[label][v] = struct{}{}
+		}
+	}
+	return g
+}
+
+// apply returns values limited by the policy and the reason if they are collapsed
+// values are not modified because they may be a slice of the caller
+func (g *labelGuard) apply(names []string, values []string) ([]string, string) {
+	limited, reason := values, ""
+	for i, name := range names {
+		allowed, ok := g.allowed[name]
+		if !ok {
+			continue
+		}
+		if _, ok := allowed[values[i]]; ok {
+			continue
+		}
+		if reason == "" {
+			limited = slices.Clone(values)
+			reason = ReasonNotAllowed
+		}
+		limited[i] = OtherValue
+	}
+	if g.maxLabelSets == 0 {
+		return limited, reason
+	}
+
+	key := strings.Join(limited, "\xff")
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.seen[key]; ok {
+		return limited, reason
+	}
+	if len(g.seen) < g.maxLabelSets {
+		g.seen[key] = struct{}{}
+		return limited, reason
+	}
+	other := make([]string, len(values))
+	for i := range other {
+		other[i] = OtherValue
+	}
+	return other, ReasonLimit
+}
+
+// SetLabelPolicy sets a policy limiting label values of the metric
+// it can be called before or after the metric is registered, but label sets observed before are not limited
+func (m *Client) SetLabelPolicy(name string, p LabelPolicy) {
+	m.RegisterCounter(LabelSetsCollapsedTotal, "ラベルポリシーにより集約されたラベルセットの数", "metric", "reason")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.labelGuardMap[name] = newLabelGuard(p)
+}
+
+// limitLabels returns label values limited by the policy of the metric
+// values are returned as is if the metric has no policy or the number of values is wrong
+func (m *Client) limitLabels(name string, values []string) []string {
+	m.mu.RLock()
+	g, ok := m.labelGuardMap[name]
+	names := m.labelNamesMap[name]
+	collapsed := m.counterVecMap[LabelSetsCollapsedTotal]
+	m.mu.RUnlock()
+	if !ok || len(names) != len(values) {
+		return values
+	}
+
+	limited, reason := g.apply(names, values)
+	if reason != "" {
+		collapsed.WithLabelValues(name, reason).Inc()
+	}
+	return limited
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestLabelPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		policy  LabelPolicy
+		observe func(m *Client)
+		want    map[string]float64
+		// key: reason
+		wantCollapsed map[string]float64
+	}{
+		"success: no_policy": {
+			observe: func(m *Client) {
+				m.Count("policy_counter", 1, "a", "x")
+				m.Count("policy_counter", 1, "b", "x")
+			},
+			want: map[string]float64{"a/x": 1, "b/x": 1},
+		},
+		"success: within_limit": {
+			policy: LabelPolicy{MaxLabelSets: 2},
+			observe: func(m *Client) {
+				m.Count("policy_counter", 1, "a", "x")
+				m.Count("policy_counter", 1, "b", "x")
+				m.Count("policy_counter", 1, "a", "x")
+			},
+			want: map[string]float64{"a/x": 2, "b/x": 1},
+		},
+		"success: overflow_is_collapsed": {
+			policy: LabelPolicy{MaxLabelSets: 2},
+			observe: func(m *Client) {
+				m.Count("policy_counter", 1, "a", "x")
+				m.Count("policy_counter", 1, "b", "x")
+				m.Count("policy_counter", 1, "c", "x")
+				m.Count("policy_counter", 1, "d", "y")
+				// label sets seen before the limit are kept
+				m.Count("policy_counter", 1, "a", "x")
+			},
+			want:          map[string]float64{"a/x": 2, "b/x": 1, "__other__/__other__": 2},
+			wantCollapsed: map[string]float64{ReasonLimit: 2},
+		},
+		"success: not_allowed_is_collapsed": {
+			policy: LabelPolicy{AllowedValues: map[string][]string{"kind": {"a", "b"}}},
+			observe: func(m *Client) {
+				m.Count("policy_counter", 1, "a", "x")
+				m.Count("policy_counter", 1, "c", "y")
+				m.Count("policy_counter", 1, "d", "y")
+			},
+			want:          map[string]float64{"a/x": 1, "__other__/y": 2},
+			wantCollapsed: map[string]float64{ReasonNotAllowed: 2},
+		},
+		"success: allowed_values_and_limit": {
+			policy: LabelPolicy{MaxLabelSets: 1, AllowedValues: map[string][]string{"kind": {"a"}}},
+			observe: func(m *Client) {
+				m.Count("policy_counter", 1, "b", "x")
+				m.Count("policy_counter", 1, "c", "x")
+				m.Count("policy_counter", 1, "a", "x")
+			},
+			want:          map[string]float64{"__other__/x": 2, "__other__/__other__": 1},
+			wantCollapsed: map[string]float64{ReasonNotAllowed: 2, ReasonLimit: 1},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			m := newTestClient()
+			m.RegisterCounter("policy_counter", "dummy", "kind", "value")
+			if tt.policy.MaxLabelSets > 0 || tt.policy.AllowedValues != nil {
+				m.SetLabelPolicy("policy_counter", tt.policy)
+			}
+			tt.observe(m)
+
+			got := map[string]float64{}
+			gotCollapsed := map[string]float64{}
+			families, err := m.Gatherer().Gather()
+			if err != nil {
+				t.Fatalf("failed to gather metrics: %v", err)
+			}
+			for _, f := range families {
+				for _, metric := range f.GetMetric() {
+					labels := map[string]string{}
+					for _, l := range metric.GetLabel() {
+						labels[l.GetName()] = l.GetValue()
+					}
+					switch f.GetName() {
+					case "policy_counter":
+						got[labels["kind"]+"/"+labels["value"]] = metric.GetCounter().GetValue()
+					case LabelSetsCollapsedTotal:
+						if labels["metric"] != "policy_counter" {
+							t.Errorf("unexpected metric label: %v", labels["metric"])
+						}
+						gotCollapsed[labels["reason"]] = metric.GetCounter().GetValue()
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected metrics: %v", diff)
+			}
+			if tt.wantCollapsed == nil {
+				tt.wantCollapsed = map[string]float64{}
+			}
+			if diff := cmp.Diff(tt.wantCollapsed, gotCollapsed); diff != "" {
+				t.Errorf("unexpected collapsed label sets: %v", diff)
+			}
+		})
+	}
+}
+
+func TestLabelPolicyTyped(t *testing.T) {
+	m := newTestClient()
+	counter := NewCounter[fruitLabels](m, "typed_policy_counter", "dummy")
+	m.SetLabelPolicy("typed_policy_counter", LabelPolicy{AllowedValues: map[string][]string{"taste": {"sweet"}}})
+	counter.Inc(fruitLabels{Kind: "apple", Taste: "sweet"})
+	counter.Inc(fruitLabels{Kind: "lemon", Taste: "sour"})
+
+	got := map[string]float64{}
+	for _, values := range [][]string{{"apple", "sweet"}, {"lemon", OtherValue}} {
+		c, err := m.counterVecMap["typed_policy_counter"].GetMetricWithLabelValues(values...)
+		if err != nil {
+			t.Fatalf("failed to get metric: %v", err)
+		}
+		metric := &dto.Metric{}
+		if err := c.Write(metric); err != nil {
+			t.Fatalf("failed to get metric: %v", err)
+		}
+		got[values[0]] = metric.GetCounter().GetValue()
+	}
+	if diff := cmp.Diff(map[string]float64{"apple": 1, "lemon": 1}, got); diff != "" {
+		t.Errorf("unexpected metrics: %v", diff)
+	}
+}
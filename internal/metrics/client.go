@@ -1,54 +1,130 @@
 package metrics
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// NativeHistogramOpts is options of native (sparse) histograms
+// https://prometheus.io/docs/specs/native_histograms/
+type NativeHistogramOpts struct {
+	// BucketFactor is the max ratio of upper and lower bounds of buckets (ex. 1.1), it must be greater than 1
+	BucketFactor float64
+	// ZeroThreshold is the width of the zero bucket, prometheus.DefNativeHistogramZeroThreshold if 0
+	ZeroThreshold float64
+	// MaxBucketNumber limits the number of buckets by lowering the resolution (0 is unlimited)
+	MaxBucketNumber uint32
+	// Buckets are classic buckets exposed together for scrapers without native histograms (nil is none)
+	Buckets []float64
+}
+
 // Client is a struct that manages metrics
 // key: metric name, value: metric
+// it is safe for concurrent use
 type Client struct {
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+
+	mu              sync.RWMutex
 	counterVecMap   map[string]*prometheus.CounterVec
 	gaugeVecMap     map[string]*prometheus.GaugeVec
 	histogramVecMap map[string]*prometheus.HistogramVec
+	summaryVecMap   map[string]*prometheus.SummaryVec
+	// labelNamesMap is label names of each metric
+	labelNamesMap map[string][]string
+	// labelGuardMap is label policies of each metric
+	labelGuardMap map[string]*labelGuard
 }
 
-// NewClient is a constructor for Client
+// NewClient is a constructor for Client registering metrics to the global registry
 func NewClient() *Client {
+	return NewClientWithRegistry(prometheus.DefaultRegisterer, prometheus.DefaultGatherer)
+}
+
+// NewClientWithRegistry is a constructor for Client registering metrics to the given registry
+// clients with their own registries have isolated metrics (ex. prometheus.NewRegistry() in tests)
+func NewClientWithRegistry(registerer prometheus.Registerer, gatherer prometheus.Gatherer) *Client {
 	return &Client{
+		registerer:      registerer,
+		gatherer:        gatherer,
 		counterVecMap:   make(map[string]*prometheus.CounterVec),
 		gaugeVecMap:     make(map[string]*prometheus.GaugeVec),
 		histogramVecMap: make(map[string]*prometheus.HistogramVec),
+		summaryVecMap:   make(map[string]*prometheus.SummaryVec),
+		labelNamesMap:   make(map[string][]string),
+		labelGuardMap:   make(map[string]*labelGuard),
 	}
 }
 
+// Registerer returns the registerer of metrics
+func (m *Client) Registerer() prometheus.Registerer {
+	return m.registerer
+}
+
+// Gatherer returns the gatherer of metrics
+func (m *Client) Gatherer() prometheus.Gatherer {
+	return m.gatherer
+}
+
+// Handler returns a handler exposing metrics of the registry
+// the format is negotiated by Accept, native histograms are exposed only in the protobuf format
+// and exemplars are exposed only in the OpenMetrics or protobuf format
+func (m *Client) Handler() http.Handler {
+	return promhttp.InstrumentMetricHandler(m.registerer, promhttp.HandlerFor(m.gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+}
+
 // RegisterCounter is registers counter metrics
+// registering the same name again is a no-op
 func (m *Client) RegisterCounter(name string, help string, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.counterVecMap[name]; ok {
+		return
+	}
 	c := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: name,
 			Help: help,
 		}, labels,
 	)
-	prometheus.MustRegister(c)
-	m.counterVecMap[name] = c
+	m.counterVecMap[name] = register(m.registerer, c)
+	m.labelNamesMap[name] = labels
 }
 
 // RegisterGauge is registers gauge metrics
+// registering the same name again is a no-op
 func (m *Client) RegisterGauge(name string, help string, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.gaugeVecMap[name]; ok {
+		return
+	}
 	g := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: name,
 			Help: help,
 		}, labels,
 	)
-	prometheus.MustRegister(g)
-	m.gaugeVecMap[name] = g
+	m.gaugeVecMap[name] = register(m.registerer, g)
+	m.labelNamesMap[name] = labels
 }
 
 // RegisterHistogram is registers histogram metrics
+// registering the same name again is a no-op
 func (m *Client) RegisterHistogram(name string, help string, buckets []float64, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.histogramVecMap[name]; ok {
+		return
+	}
 	h := prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    name,
@@ -56,18 +132,81 @@ func (m *Client) RegisterHistogram(name string, help string, buckets []float64,
 			Buckets: buckets,
 		}, labels,
 	)
-	prometheus.MustRegister(h)
-	m.histogramVecMap[name] = h
+	m.histogramVecMap[name] = register(m.registerer, h)
+	m.labelNamesMap[name] = labels
+}
+
+// RegisterNativeHistogram is registers native histogram metrics
+// they are observed by ObserveHistogram, registering the same name again is a no-op
+func (m *Client) RegisterNativeHistogram(name string, help string, opts NativeHistogramOpts, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.histogramVecMap[name]; ok {
+		return
+	}
+	h := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:                           name,
+			Help:                           help,
+			Buckets:                        opts.Buckets,
+			NativeHistogramBucketFactor:    opts.BucketFactor,
+			NativeHistogramZeroThreshold:   opts.ZeroThreshold,
+			NativeHistogramMaxBucketNumber: opts.MaxBucketNumber,
+		}, labels,
+	)
+	m.histogramVecMap[name] = register(m.registerer, h)
+	m.labelNamesMap[name] = labels
+}
+
+// RegisterSummary is registers summary metrics
+// objectives are quantiles and their allowed errors (ex. {0.5: 0.05, 0.99: 0.001})
+// quantiles are calculated from observations in the last maxAge, prometheus.DefMaxAge if 0
+// registering the same name again is a no-op
+func (m *Client) RegisterSummary(name string, help string, objectives map[float64]float64, maxAge time.Duration, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.summaryVecMap[name]; ok {
+		return
+	}
+	s := prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       name,
+			Help:       help,
+			Objectives: objectives,
+			MaxAge:     maxAge,
+		}, labels,
+	)
+	m.summaryVecMap[name] = register(m.registerer, s)
+	m.labelNamesMap[name] = labels
+}
+
+// register registers a collector, or returns the collector already registered by another client
+// it panics if a different metric with the same name is registered as prometheus.MustRegister does
+func register[T prometheus.Collector](registerer prometheus.Registerer, c T) T {
+	err := registerer.Register(c)
+	if err == nil {
+		return c
+	}
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			return existing
+		}
+	}
+	panic(fmt.Errorf("failed to register metrics: %w", err))
 }
 
 // Count is increments counter metrics
 func (m *Client) Count(name string, value float64, labels ...string) {
+	m.mu.RLock()
 	cv, ok := m.counterVecMap[name]
+	m.mu.RUnlock()
 	if !ok {
 		slog.Warn("counter not found", "name", name)
 		return
 	}
 
+	labels = m.limitLabels(name, labels)
 	counter, err := cv.GetMetricWithLabelValues(labels...)
 	if err != nil {
 		slog.Warn("counter not found", "name", name, "labels", labels)
@@ -78,12 +217,15 @@ func (m *Client) Count(name string, value float64, labels ...string) {
 
 // SetGauge is sets gauge metrics
 func (m *Client) SetGauge(name string, value float64, labels ...string) {
+	m.mu.RLock()
 	gv, ok := m.gaugeVecMap[name]
+	m.mu.RUnlock()
 	if !ok {
 		slog.Warn("gauge not found", "name", name)
 		return
 	}
 
+	labels = m.limitLabels(name, labels)
 	gauge, err := gv.GetMetricWithLabelValues(labels...)
 	if err != nil {
 		slog.Warn("gauge not found", "name", name, "labels", labels)
@@ -94,16 +236,44 @@ func (m *Client) SetGauge(name string, value float64, labels ...string) {
 
 // ObserveHistogram is observes histogram metrics
 func (m *Client) ObserveHistogram(name string, value float64, labels ...string) {
+	m.ObserveHistogramContext(context.Background(), name, value, labels...)
+}
+
+// ObserveHistogramContext is observes histogram metrics with the trace ID of ctx as an exemplar
+// exemplars are exposed in the OpenMetrics or protobuf format
+func (m *Client) ObserveHistogramContext(ctx context.Context, name string, value float64, labels ...string) {
+	m.mu.RLock()
 	hv, ok := m.histogramVecMap[name]
+	m.mu.RUnlock()
 	if !ok {
 		slog.Warn("histogram not found", "name", name)
 		return
 	}
 
+	labels = m.limitLabels(name, labels)
 	histogram, err := hv.GetMetricWithLabelValues(labels...)
 	if err != nil {
 		slog.Warn("histogram not found", "name", name, "labels", labels)
 		return
 	}
-	histogram.Observe(value)
+	observe(ctx, histogram, value)
+}
+
+// ObserveSummary is observes summary metrics
+func (m *Client) ObserveSummary(name string, value float64, labels ...string) {
+	m.mu.RLock()
+	sv, ok := m.summaryVecMap[name]
+	m.mu.RUnlock()
+	if !ok {
+		slog.Warn("summary not found", "name", name)
+		return
+	}
+
+	labels = m.limitLabels(name, labels)
+	summary, err := sv.GetMetricWithLabelValues(labels...)
+	if err != nil {
+		slog.Warn("summary not found", "name", name, "labels", labels)
+		return
+	}
+	summary.Observe(value)
 }
@@ -3,10 +3,16 @@ package metrics
 import (
 	"fmt"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 )
 
 const tolerance = 1e-6
@@ -30,6 +36,12 @@ func compareFloat64(a, b float64) bool {
 	return cmp.Diff(a, b, opt) != ""
 }
 
+// newTestClient returns a client with its own registry, so that tests do not share metrics
+func newTestClient() *Client {
+	registry := prometheus.NewRegistry()
+	return NewClientWithRegistry(registry, registry)
+}
+
 func TestCounter(t *testing.T) {
 	testCases := map[string]struct {
 		targetMetrics map[string][]string
@@ -144,7 +156,7 @@ func TestCounter(t *testing.T) {
 		t.Run(tc, func(t *testing.T) {
 			t.Parallel()
 
-			m := NewClient()
+			m := newTestClient()
 			for name, labels := range tt.targetMetrics {
 				fmt.Println(name, labels)
 				m.RegisterCounter(name, "dummy", labels...)
@@ -278,7 +290,7 @@ func TestGauge(t *testing.T) {
 		t.Run(tc, func(t *testing.T) {
 			t.Parallel()
 
-			m := NewClient()
+			m := newTestClient()
 			for name, labels := range tt.targetMetrics {
 				fmt.Println(name, labels)
 				m.RegisterGauge(name, "dummy", labels...)
@@ -379,7 +391,7 @@ func TestHistogram(t *testing.T) {
 		t.Run(tc, func(t *testing.T) {
 			t.Parallel()
 
-			m := NewClient()
+			m := newTestClient()
 			for name, hist := range tt.targetMetrics {
 				m.RegisterHistogram(name, "dummy", hist.buckets, hist.labels...)
 			}
@@ -415,3 +427,261 @@ func TestHistogram(t *testing.T) {
 		})
 	}
 }
+
+func TestNativeHistogram(t *testing.T) {
+	type expectedNativeHistogram struct {
+		Schema        int32
+		ZeroThreshold float64
+		ZeroCount     uint64
+		SampleCount   uint64
+		ClassicCount  int
+	}
+
+	testCases := map[string]struct {
+		opts        NativeHistogramOpts
+		labels      []string
+		observeFunc func(m *Client)
+		want        expectedNativeHistogram
+	}{
+		"success: native_only": {
+			opts: NativeHistogramOpts{BucketFactor: 1.1, ZeroThreshold: 0.001},
+			observeFunc: func(m *Client) {
+				m.ObserveHistogram("test_native_histogram", 0)
+				m.ObserveHistogram("test_native_histogram", 0.0001)
+				m.ObserveHistogram("test_native_histogram", 1)
+				m.ObserveHistogram("test_native_histogram", 2.5)
+			},
+			want: expectedNativeHistogram{
+				Schema:        3, // 2^(2^-3) = 1.09 is the largest factor within 1.1
+				ZeroThreshold: 0.001,
+				ZeroCount:     2, // 0, 0.0001
+				SampleCount:   4,
+				ClassicCount:  0,
+			},
+		},
+		"success: with_classic_buckets": {
+			opts:   NativeHistogramOpts{BucketFactor: 2, Buckets: []float64{1, 2}},
+			labels: []string{"test_label"},
+			observeFunc: func(m *Client) {
+				m.ObserveHistogram("test_native_histogram", 1, "test_label")
+				m.ObserveHistogram("test_native_histogram", 3, "test_label")
+			},
+			want: expectedNativeHistogram{
+				Schema:        0, // 2^(2^0) = 2
+				ZeroThreshold: prometheus.DefNativeHistogramZeroThreshold,
+				ZeroCount:     0,
+				SampleCount:   2,
+				ClassicCount:  2, // 1, 2 (+Inf is implicit)
+			},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			m := newTestClient()
+			labelNames := make([]string, len(tt.labels))
+			for i := range tt.labels {
+				labelNames[i] = fmt.Sprintf("label%d", i)
+			}
+			m.RegisterNativeHistogram("test_native_histogram", "dummy", tt.opts, labelNames...)
+			tt.observeFunc(m)
+
+			families, err := m.Gatherer().Gather()
+			if err != nil {
+				t.Fatalf("failed to gather metrics: %v", err)
+			}
+			if len(families) != 1 || len(families[0].GetMetric()) != 1 {
+				t.Fatalf("unexpected metrics: %v", families)
+			}
+			hist := families[0].GetMetric()[0].GetHistogram()
+			got := expectedNativeHistogram{
+				Schema:        hist.GetSchema(),
+				ZeroThreshold: hist.GetZeroThreshold(),
+				ZeroCount:     hist.GetZeroCount(),
+				SampleCount:   hist.GetSampleCount(),
+				ClassicCount:  len(hist.GetBucket()),
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected histogram: %v", diff)
+			}
+			if len(hist.GetPositiveSpan()) == 0 {
+				t.Errorf("positive observations should have spans")
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	type expectedQuantile struct {
+		quantile float64
+		value    float64
+		err      float64
+	}
+
+	testCases := map[string]struct {
+		objectives map[float64]float64
+		labels     []string
+		want       []expectedQuantile
+	}{
+		"success: no_label": {
+			objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01},
+			want: []expectedQuantile{
+				{quantile: 0.5, value: 50, err: 5},
+				{quantile: 0.9, value: 90, err: 1},
+			},
+		},
+		"success: single_label": {
+			objectives: map[float64]float64{0.99: 0.001},
+			labels:     []string{"test_label"},
+			want: []expectedQuantile{
+				{quantile: 0.99, value: 99, err: 0.1},
+			},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			m := newTestClient()
+			labelNames := make([]string, len(tt.labels))
+			for i := range tt.labels {
+				labelNames[i] = fmt.Sprintf("label%d", i)
+			}
+			m.RegisterSummary("test_summary", "dummy", tt.objectives, time.Minute, labelNames...)
+			for i := 1; i <= 100; i++ {
+				m.ObserveSummary("test_summary", float64(i), tt.labels...)
+			}
+
+			metric := &dto.Metric{}
+			if err := m.summaryVecMap["test_summary"].WithLabelValues(tt.labels...).(prometheus.Metric).Write(metric); err != nil {
+				t.Fatalf("failed to get metric: %v", err)
+			}
+			summary := metric.GetSummary()
+			if diff := cmp.Diff(uint64(100), summary.GetSampleCount()); diff != "" {
+				t.Errorf("unexpected sample count: %v", diff)
+			}
+			if compareFloat64(5050, summary.GetSampleSum()) {
+				t.Errorf("want %v, but got %v", 5050, summary.GetSampleSum())
+			}
+			gotQuantiles := summary.GetQuantile()
+			if len(gotQuantiles) != len(tt.want) {
+				t.Fatalf("unexpected quantiles: %v", gotQuantiles)
+			}
+			for i, w := range tt.want {
+				got := gotQuantiles[i]
+				if compareFloat64(w.quantile, got.GetQuantile()) {
+					t.Errorf("want quantile %v, but got %v", w.quantile, got.GetQuantile())
+				}
+				if math.Abs(w.value-got.GetValue()) > w.err {
+					t.Errorf("want %v±%v for quantile %v, but got %v", w.value, w.err, w.quantile, got.GetValue())
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerProtobuf(t *testing.T) {
+	m := newTestClient()
+	m.RegisterNativeHistogram("test_native_histogram", "dummy", NativeHistogramOpts{BucketFactor: 1.1})
+	m.ObserveHistogram("test_native_histogram", 1)
+
+	// native histograms are scraped in the protobuf format
+	format := expfmt.NewFormat(expfmt.TypeProtoDelim)
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", string(format))
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+
+	if diff := cmp.Diff(format, expfmt.ResponseFormat(rec.Header())); diff != "" {
+		t.Fatalf("unexpected format: %v", diff)
+	}
+	dec := expfmt.NewDecoder(rec.Body, format)
+	for {
+		family := &dto.MetricFamily{}
+		if err := dec.Decode(family); err != nil {
+			t.Fatalf("native histogram is not exposed: %v", err)
+		}
+		if family.GetName() != "test_native_histogram" {
+			continue
+		}
+		if diff := cmp.Diff(int32(3), family.GetMetric()[0].GetHistogram().GetSchema()); diff != "" {
+			t.Errorf("unexpected schema: %v", diff)
+		}
+		return
+	}
+}
+
+func TestRegisterIdempotent(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	// registering the same name again does not panic
+	m := NewClientWithRegistry(registry, registry)
+	m.RegisterCounter("idempotent_counter", "dummy", "label")
+	m.RegisterCounter("idempotent_counter", "dummy", "label")
+	m.RegisterGauge("idempotent_gauge", "dummy")
+	m.RegisterGauge("idempotent_gauge", "dummy")
+	m.RegisterHistogram("idempotent_histogram", "dummy", []float64{1})
+	m.RegisterHistogram("idempotent_histogram", "dummy", []float64{1})
+	m.RegisterSummary("idempotent_summary", "dummy", nil, 0)
+	m.RegisterSummary("idempotent_summary", "dummy", nil, 0)
+
+	// clients sharing a registry share metrics
+	another := NewClientWithRegistry(registry, registry)
+	another.RegisterCounter("idempotent_counter", "dummy", "label")
+	m.Count("idempotent_counter", 1, "a")
+	another.Count("idempotent_counter", 2, "a")
+
+	families, err := m.Gatherer().Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	var got float64
+	for _, f := range families {
+		if f.GetName() == "idempotent_counter" {
+			got = f.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	if compareFloat64(3, got) {
+		t.Errorf("want %v, but got %v", 3, got)
+	}
+}
+
+func TestRegisterConflict(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	NewClientWithRegistry(registry, registry).RegisterCounter("conflict_counter", "dummy", "label")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering different labels with the same name should panic")
+		}
+	}()
+	NewClientWithRegistry(registry, registry).RegisterCounter("conflict_counter", "dummy", "another_label")
+}
+
+func TestConcurrentUse(t *testing.T) {
+	m := newTestClient()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.RegisterCounter("concurrent_counter", "dummy", "label")
+			m.Count("concurrent_counter", 1, "a")
+		}()
+	}
+	wg.Wait()
+
+	metric := &dto.Metric{}
+	if err := m.counterVecMap["concurrent_counter"].WithLabelValues("a").Write(metric); err != nil {
+		t.Fatalf("failed to get metric: %v", err)
+	}
+	if compareFloat64(10, metric.Counter.GetValue()) {
+		t.Errorf("want %v, but got %v", 10, metric.Counter.GetValue())
+	}
+}
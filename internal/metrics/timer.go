@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDLabel is the label of exemplars holding the trace ID
+const TraceIDLabel = "trace_id"
+
+// StartTimer starts measuring a duration, it is observed in seconds by the returned function
+// name is a histogram or a summary (ex. defer m.StartTimer(RequestDuration, "users")())
+func (m *Client) StartTimer(name string, labels ...string) func() {
+	return m.StartTimerContext(context.Background(), name, labels...)
+}
+
+// StartTimerContext is StartTimer observing the trace ID of ctx as an exemplar of histograms
+func (m *Client) StartTimerContext(ctx context.Context, name string, labels ...string) func() {
+	start := time.Now()
+	return func() {
+		d := time.Since(start).Seconds()
+		m.mu.RLock()
+		_, isSummary := m.summaryVecMap[name]
+		m.mu.RUnlock()
+		if isSummary {
+			m.ObserveSummary(name, d, labels...)
+			return
+		}
+		m.ObserveHistogramContext(ctx, name, d, labels...)
+	}
+}
+
+// Track increments an in-flight gauge, it is decremented by the returned function
+// (ex. defer m.Track(ctx, InFlightRequests)())
+func (m *Client) Track(ctx context.Context, name string, labels ...string) func() {
+	m.mu.RLock()
+	gv, ok := m.gaugeVecMap[name]
+	m.mu.RUnlock()
+	if !ok {
+		slog.WarnContext(ctx, "gauge not found", "name", name)
+		return func() {}
+	}
+
+	gauge, err := gv.GetMetricWithLabelValues(m.limitLabels(name, labels)...)
+	if err != nil {
+		slog.WarnContext(ctx, "gauge not found", "name", name, "labels", labels)
+		return func() {}
+	}
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// observe observes value with the trace ID of ctx as an exemplar
+// exemplars are attached only to sampled traces, because others are not exported
+func observe(ctx context.Context, o prometheus.Observer, value float64) {
+	sc := trace.SpanContextFromContext(ctx)
+	eo, ok := o.(prometheus.ExemplarObserver)
+	if !ok || !sc.IsSampled() {
+		o.Observe(value)
+		return
+	}
+	eo.ObserveWithExemplar(value, prometheus.Labels{TraceIDLabel: sc.TraceID().String()})
+}
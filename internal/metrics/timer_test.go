@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// gatherMetrics returns metrics of the name
+func gatherMetrics(t *testing.T, m *Client, name string) []*dto.Metric {
+	t.Helper()
+	families, err := m.Gatherer().Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() == name {
+			return f.GetMetric()
+		}
+	}
+	return nil
+}
+
+func TestStartTimer(t *testing.T) {
+	m := newTestClient()
+	m.RegisterHistogram("timer_histogram", "dummy", []float64{0.001, 10}, "label")
+	m.RegisterSummary("timer_summary", "dummy", nil, 0)
+
+	stop := m.StartTimer("timer_histogram", "a")
+	time.Sleep(2 * time.Millisecond)
+	stop()
+	m.StartTimer("timer_summary")()
+
+	histogram := gatherMetrics(t, m, "timer_histogram")
+	if len(histogram) != 1 {
+		t.Fatalf("histogram should be observed")
+	}
+	buckets := histogram[0].GetHistogram().GetBucket()
+	// the duration is observed in seconds
+	got := []uint64{buckets[0].GetCumulativeCount(), buckets[1].GetCumulativeCount()}
+	if diff := cmp.Diff([]uint64{0, 1}, got); diff != "" {
+		t.Errorf("unexpected buckets: %v", diff)
+	}
+	summary := gatherMetrics(t, m, "timer_summary")
+	if len(summary) != 1 || summary[0].GetSummary().GetSampleCount() != 1 {
+		t.Errorf("summary should be observed: %v", summary)
+	}
+}
+
+func TestTrack(t *testing.T) {
+	m := newTestClient()
+	m.RegisterGauge("in_flight", "dummy", "label")
+	ctx := context.Background()
+
+	done1 := m.Track(ctx, "in_flight", "a")
+	done2 := m.Track(ctx, "in_flight", "a")
+	if got := gatherMetrics(t, m, "in_flight")[0].GetGauge().GetValue(); got != 2 {
+		t.Errorf("unexpected in-flight: %v", got)
+	}
+	done1()
+	done2()
+	if got := gatherMetrics(t, m, "in_flight")[0].GetGauge().GetValue(); got != 0 {
+		t.Errorf("unexpected in-flight: %v", got)
+	}
+
+	// not registered gauges are ignored
+	m.Track(ctx, "not_registered")()
+}
+
+func TestExemplar(t *testing.T) {
+	traceID := trace.TraceID{0x01, 0x02, 0x03}
+	testCases := map[string]struct {
+		flags trace.TraceFlags
+		want  []*dto.LabelPair
+	}{
+		"success: sampled": {
+			flags: trace.FlagsSampled,
+			want:  []*dto.LabelPair{{Name: ptr(TraceIDLabel), Value: ptr(traceID.String())}},
+		},
+		"success: not_sampled": {
+			flags: 0,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			m := newTestClient()
+			m.RegisterHistogram("exemplar_histogram", "dummy", []float64{1})
+			sc := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     trace.SpanID{0x01},
+				TraceFlags: tt.flags,
+			})
+			ctx := trace.ContextWithSpanContext(context.Background(), sc)
+			m.ObserveHistogramContext(ctx, "exemplar_histogram", 0.5)
+
+			var got []*dto.LabelPair
+			if e := gatherMetrics(t, m, "exemplar_histogram")[0].GetHistogram().GetBucket()[0].GetExemplar(); e != nil {
+				got = e.GetLabel()
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b *dto.LabelPair) bool {
+				return a.GetName() == b.GetName() && a.GetValue() == b.GetValue()
+			})); diff != "" {
+				t.Errorf("unexpected exemplar: %v", diff)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// labelTag is the struct tag of label names (ex. struct{ Operation string `label:"operation"` })
+const labelTag = "label"
+
+// NoLabels is labels of metrics without labels
+type NoLabels struct{}
+
+// labelSet is label names of a labels struct and indexes of their fields
+type labelSet struct {
+	names   []string
+	indexes []int
+}
+
+// labelSets caches labelSet of each labels struct
+var labelSets sync.Map
+
+// labelSetOf returns the labelSet of L
+// it panics if L is not a struct of string fields tagged with label names, which is a bug of the caller
+func labelSetOf[L any]() labelSet {
+	t := reflect.TypeFor[L]()
+	if ls, ok := labelSets.Load(t); ok {
+		return ls.(labelSet)
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("labels must be a struct: %v", t))
+	}
+	ls := labelSet{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := f.Tag.Lookup(labelTag)
+		if !ok || f.Type.Kind() != reflect.String {
+			panic(fmt.Errorf("field %s of %v must be a string with a label tag", f.Name, t))
+		}
+		ls.names = append(ls.names, name)
+		ls.indexes = append(ls.indexes, i)
+	}
+	labelSets.Store(t, ls)
+	return ls
+}
+
+// values returns label values of l in the order of names
+func (ls labelSet) values(l any) []string {
+	v := reflect.ValueOf(l)
+	values := make([]string, len(ls.indexes))
+	for i, index := range ls.indexes {
+		values[i] = v.Field(index).String()
+	}
+	return values
+}
+
+// handle is a metric of a typed handle
+type handle struct {
+	m      *Client
+	name   string
+	labels labelSet
+}
+
+// values returns label values of l limited by the policy of the metric
+func (h handle) values(l any) []string {
+	return h.m.limitLabels(h.name, h.labels.values(l))
+}
+
+// checkLabels panics if the metric is registered with other labels than the labels struct
+// it must be called with the lock of m
+func (m *Client) checkLabels(name string, ls labelSet) {
+	if registered := m.labelNamesMap[name]; !slices.Equal(registered, ls.names) {
+		panic(fmt.Errorf("metric %s is registered with labels %v, not %v", name, registered, ls.names))
+	}
+}
+
+// Counter is a counter with labels L
+type Counter[L any] struct {
+	handle
+	vec *prometheus.CounterVec
+}
+
+// NewCounter registers a counter and returns its handle
+// it shares the metric with RegisterCounter, so the string-based methods keep working
+func NewCounter[L any](m *Client, name string, help string) *Counter[L] {
+	ls := labelSetOf[L]()
+	m.RegisterCounter(name, help, ls.names...)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.checkLabels(name, ls)
+	return &Counter[L]{handle: handle{m: m, name: name, labels: ls}, vec: m.counterVecMap[name]}
+}
+
+// Add adds value to the counter of labels
+func (c *Counter[L]) Add(labels L, value float64) {
+	c.vec.WithLabelValues(c.values(labels)...).Add(value)
+}
+
+// Inc increments the counter of labels
+func (c *Counter[L]) Inc(labels L) {
+	c.Add(labels, 1)
+}
+
+// Gauge is a gauge with labels L
+type Gauge[L any] struct {
+	handle
+	vec *prometheus.GaugeVec
+}
+
+// NewGauge registers a gauge and returns its handle
+// it shares the metric with RegisterGauge, so the string-based methods keep working
+func NewGauge[L any](m *Client, name string, help string) *Gauge[L] {
+	ls := labelSetOf[L]()
+	m.RegisterGauge(name, help, ls.names...)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.checkLabels(name, ls)
+	return &Gauge[L]{handle: handle{m: m, name: name, labels: ls}, vec: m.gaugeVecMap[name]}
+}
+
+// Set sets value to the gauge of labels
+func (g *Gauge[L]) Set(labels L, value float64) {
+	g.vec.WithLabelValues(g.values(labels)...).Set(value)
+}
+
+// Add adds value to the gauge of labels, value may be negative
+func (g *Gauge[L]) Add(labels L, value float64) {
+	g.vec.WithLabelValues(g.values(labels)...).Add(value)
+}
+
+// Histogram is a histogram with labels L
+type Histogram[L any] struct {
+	handle
+	vec *prometheus.HistogramVec
+}
+
+// NewHistogram registers a histogram and returns its handle
+// it shares the metric with RegisterHistogram, so the string-based methods keep working
+func NewHistogram[L any](m *Client, name string, help string, buckets []float64) *Histogram[L] {
+	ls := labelSetOf[L]()
+	m.RegisterHistogram(name, help, buckets, ls.names...)
+	return histogramOf[L](m, name, ls)
+}
+
+// NewNativeHistogram registers a native histogram and returns its handle
+// it shares the metric with RegisterNativeHistogram, so the string-based methods keep working
+func NewNativeHistogram[L any](m *Client, name string, help string, opts NativeHistogramOpts) *Histogram[L] {
+	ls := labelSetOf[L]()
+	m.RegisterNativeHistogram(name, help, opts, ls.names...)
+	return histogramOf[L](m, name, ls)
+}
+
+func histogramOf[L any](m *Client, name string, ls labelSet) *Histogram[L] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.checkLabels(name, ls)
+	return &Histogram[L]{handle: handle{m: m, name: name, labels: ls}, vec: m.histogramVecMap[name]}
+}
+
+// Observe observes value in the histogram of labels
+func (h *Histogram[L]) Observe(labels L, value float64) {
+	h.vec.WithLabelValues(h.values(labels)...).Observe(value)
+}
+
+// ObserveContext observes value in the histogram of labels with the trace ID of ctx as an exemplar
+func (h *Histogram[L]) ObserveContext(ctx context.Context, labels L, value float64) {
+	observe(ctx, h.vec.WithLabelValues(h.values(labels)...), value)
+}
+
+// Summary is a summary with labels L
+type Summary[L any] struct {
+	handle
+	vec *prometheus.SummaryVec
+}
+
+// NewSummary registers a summary and returns its handle
+// it shares the metric with RegisterSummary, so the string-based methods keep working
+func NewSummary[L any](m *Client, name string, help string, objectives map[float64]float64, maxAge time.Duration) *Summary[L] {
+	ls := labelSetOf[L]()
+	m.RegisterSummary(name, help, objectives, maxAge, ls.names...)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.checkLabels(name, ls)
+	return &Summary[L]{handle: handle{m: m, name: name, labels: ls}, vec: m.summaryVecMap[name]}
+}
+
+// Observe observes value in the summary of labels
+func (s *Summary[L]) Observe(labels L, value float64) {
+	s.vec.WithLabelValues(s.values(labels)...).Observe(value)
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type fruitLabels struct {
+	Kind  string `label:"kind"`
+	Taste string `label:"taste"`
+}
+
+func TestTypedHandles(t *testing.T) {
+	m := newTestClient()
+	apple := fruitLabels{Kind: "apple", Taste: "delicious"}
+
+	counter := NewCounter[fruitLabels](m, "typed_counter", "dummy")
+	counter.Inc(apple)
+	counter.Add(apple, 2)
+	// handles share metrics with the string-based methods
+	m.Count("typed_counter", 3, "apple", "delicious")
+
+	gauge := NewGauge[NoLabels](m, "typed_gauge", "dummy")
+	gauge.Set(NoLabels{}, 5)
+	gauge.Add(NoLabels{}, -2)
+
+	histogram := NewHistogram[fruitLabels](m, "typed_histogram", "dummy", []float64{1, 2})
+	histogram.Observe(apple, 1.5)
+	m.ObserveHistogram("typed_histogram", 0.5, "apple", "delicious")
+
+	summary := NewSummary[fruitLabels](m, "typed_summary", "dummy", map[float64]float64{0.5: 0.05}, time.Minute)
+	summary.Observe(apple, 1)
+	m.ObserveSummary("typed_summary", 3, "apple", "delicious")
+
+	got := map[string]float64{}
+	metric := &dto.Metric{}
+	if err := m.counterVecMap["typed_counter"].WithLabelValues("apple", "delicious").Write(metric); err != nil {
+		t.Fatalf("failed to get metric: %v", err)
+	}
+	got["counter"] = metric.GetCounter().GetValue()
+	if err := m.gaugeVecMap["typed_gauge"].WithLabelValues().Write(metric); err != nil {
+		t.Fatalf("failed to get metric: %v", err)
+	}
+	got["gauge"] = metric.GetGauge().GetValue()
+	families, err := m.Gatherer().Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, f := range families {
+		switch f.GetName() {
+		case "typed_histogram":
+			got["histogram"] = float64(f.GetMetric()[0].GetHistogram().GetSampleCount())
+		case "typed_summary":
+			got["summary"] = f.GetMetric()[0].GetSummary().GetSampleSum()
+		}
+	}
+
+	want := map[string]float64{
+		"counter":   6, // 1 + 2 + 3
+		"gauge":     3, // 5 - 2
+		"histogram": 2, // 1.5, 0.5
+		"summary":   4, // 1 + 3
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected metrics: %v", diff)
+	}
+}
+
+func TestTypedHandlesPanic(t *testing.T) {
+	type untagged struct {
+		Kind string
+	}
+	type notString struct {
+		Count int `label:"count"`
+	}
+
+	testCases := map[string]struct {
+		register func(m *Client)
+	}{
+		"failure: labels_differ_from_registered": {
+			register: func(m *Client) {
+				m.RegisterCounter("mismatch_counter", "dummy", "kind")
+				NewCounter[fruitLabels](m, "mismatch_counter", "dummy")
+			},
+		},
+		"failure: untagged_field": {
+			register: func(m *Client) {
+				NewCounter[untagged](m, "untagged_counter", "dummy")
+			},
+		},
+		"failure: not_string_field": {
+			register: func(m *Client) {
+				NewGauge[notString](m, "not_string_gauge", "dummy")
+			},
+		},
+		"failure: not_struct": {
+			register: func(m *Client) {
+				NewHistogram[string](m, "not_struct_histogram", "dummy", nil)
+			},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("invalid labels should panic")
+				}
+			}()
+			tt.register(newTestClient())
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+)
+
+const (
+	// DefaultLimit is the page size when neither first nor last is specified
+	DefaultLimit = 20
+	// MaxLimit is the maximum page size
+	MaxLimit = 100
+
+	cursorPrefix = "cursor:"
+)
+
+// Args is the arguments of a Relay connection field
+// https://relay.dev/graphql/connections.htm#sec-Arguments
+type Args struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// Page is a validated and decoded Args
+type Page struct {
+	// Limit is the number of items in the page
+	Limit int
+	// After is the key of the after cursor, nil if not specified
+	After *string
+	// Before is the key of the before cursor, nil if not specified
+	Before *string
+	// Backward is true when the page is requested with last
+	Backward bool
+}
+
+// PageInfo is the information about a page
+type PageInfo struct {
+	HasPreviousPage bool
+	HasNextPage     bool
+}
+
+// NewPage validates args and decodes its cursors
+func NewPage(args Args) (Page, error) {
+	if args.First != nil && args.Last != nil {
+		return Page{}, apperr.New(apperr.CodeValidation, "first", "first and last can not be specified at the same time")
+	}
+
+	page := Page{Limit: DefaultLimit}
+	switch {
+	case args.First != nil:
+		page.Limit = *args.First
+	case args.Last != nil:
+		page.Limit = *args.Last
+		page.Backward = true
+	}
+	if page.Limit < 0 || page.Limit > MaxLimit {
+		field := "first"
+		if page.Backward {
+			field = "last"
+		}
+		return Page{}, apperr.New(apperr.CodeValidation, field, "page size must be between 0 and 100")
+	}
+
+	if args.After != nil {
+		key, err := DecodeCursor(*args.After)
+		if err != nil {
+			return Page{}, apperr.Wrap(err, apperr.CodeValidation, "after", "invalid cursor")
+		}
+		page.After = &key
+	}
+	if args.Before != nil {
+		key, err := DecodeCursor(*args.Before)
+		if err != nil {
+			return Page{}, apperr.Wrap(err, apperr.CodeValidation, "before", "invalid cursor")
+		}
+		page.Before = &key
+	}
+	return page, nil
+}
+
+// Bounds returns the exclusive lower and upper bounds of keys in the page
+// ascending is the requested order of the connection
+func (p Page) Bounds(ascending bool) (lower *string, upper *string) {
+	if ascending {
+		return p.After, p.Before
+	}
+	return p.Before, p.After
+}
+
+// ScanAscending reports whether the database should be scanned in ascending order
+// when the page is requested with last, the database is scanned in the reverse order
+func (p Page) ScanAscending(ascending bool) bool {
+	return ascending != p.Backward
+}
+
+// FetchLimit is the number of rows to fetch
+// one extra row is fetched to know whether there is a next page
+func (p Page) FetchLimit() int32 {
+	return int32(p.Limit + 1)
+}
+
+// Trim trims the rows fetched with FetchLimit into the page
+// rows must be scanned in the order reported by ScanAscending
+func Trim[T any](p Page, rows []T) ([]T, PageInfo) {
+	hasMore := len(rows) > p.Limit
+	if hasMore {
+		rows = rows[:p.Limit]
+	}
+
+	if !p.Backward {
+		return rows, PageInfo{
+			HasPreviousPage: p.After != nil,
+			HasNextPage:     hasMore,
+		}
+	}
+
+	reversed := make([]T, len(rows))
+	for i := range rows {
+		reversed[len(rows)-1-i] = rows[i]
+	}
+	return reversed, PageInfo{
+		HasPreviousPage: hasMore,
+		HasNextPage:     p.Before != nil,
+	}
+}
+
+// EncodeCursor encodes a key into an opaque cursor
+func EncodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + key))
+}
+
+// DecodeCursor decodes an opaque cursor into a key
+func DecodeCursor(cursor string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+	key, ok := strings.CutPrefix(string(b), cursorPrefix)
+	if !ok {
+		return "", apperr.New(apperr.CodeValidation, "", "invalid cursor")
+	}
+	return key, nil
+}
@@ -0,0 +1,123 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNewPage(t *testing.T) {
+	testCases := map[string]struct {
+		args    Args
+		want    Page
+		wantErr bool
+	}{
+		"success: default": {
+			args: Args{},
+			want: Page{Limit: DefaultLimit},
+		},
+		"success: first_after": {
+			args: Args{First: ptr(10), After: ptr(EncodeCursor("a"))},
+			want: Page{Limit: 10, After: ptr("a")},
+		},
+		"success: last_before": {
+			args: Args{Last: ptr(5), Before: ptr(EncodeCursor("z"))},
+			want: Page{Limit: 5, Before: ptr("z"), Backward: true},
+		},
+		"failure: first_and_last": {
+			args:    Args{First: ptr(1), Last: ptr(1)},
+			wantErr: true,
+		},
+		"failure: negative": {
+			args:    Args{First: ptr(-1)},
+			wantErr: true,
+		},
+		"failure: too_large": {
+			args:    Args{Last: ptr(MaxLimit + 1)},
+			wantErr: true,
+		},
+		"failure: invalid_cursor": {
+			args:    Args{After: ptr("not a cursor")},
+			wantErr: true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewPage(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected page: %v", diff)
+			}
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	testCases := map[string]struct {
+		page     Page
+		rows     []string
+		want     []string
+		wantInfo PageInfo
+	}{
+		"success: forward_has_next": {
+			page:     Page{Limit: 2},
+			rows:     []string{"a", "b", "c"},
+			want:     []string{"a", "b"},
+			wantInfo: PageInfo{HasPreviousPage: false, HasNextPage: true},
+		},
+		"success: forward_last_page": {
+			page:     Page{Limit: 2, After: ptr("a")},
+			rows:     []string{"b", "c"},
+			want:     []string{"b", "c"},
+			wantInfo: PageInfo{HasPreviousPage: true, HasNextPage: false},
+		},
+		"success: backward_has_previous": {
+			page:     Page{Limit: 2, Backward: true},
+			rows:     []string{"c", "b", "a"},
+			want:     []string{"b", "c"},
+			wantInfo: PageInfo{HasPreviousPage: true, HasNextPage: false},
+		},
+		"success: backward_first_page": {
+			page:     Page{Limit: 2, Before: ptr("c"), Backward: true},
+			rows:     []string{"b", "a"},
+			want:     []string{"a", "b"},
+			wantInfo: PageInfo{HasPreviousPage: false, HasNextPage: true},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, info := Trim(tt.page, tt.rows)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected rows: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantInfo, info); diff != "" {
+				t.Errorf("unexpected page info: %v", diff)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	key := "01920000-0000-7000-8000-000000000000"
+	got, err := DecodeCursor(EncodeCursor(key))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(key, got); diff != "" {
+		t.Errorf("unexpected key: %v", diff)
+	}
+}
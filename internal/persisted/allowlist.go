@@ -0,0 +1,78 @@
+package persisted
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// CodeNotFound is the error code of hashes which are not registered
+	// it is the same code as APQ, but clients must not resend the query in allowlist mode
+	CodeNotFound = "PERSISTED_QUERY_NOT_FOUND"
+	// CodeNotAllowed is the error code of queries which are not registered
+	CodeNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+)
+
+// Allowlist is a gqlgen extension executing only registered queries
+// clients send either the hash in `extensions.persistedQuery` or the registered query itself
+type Allowlist struct {
+	queries map[string]string
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = (*Allowlist)(nil)
+
+// NewAllowlist is a constructor for Allowlist
+// queries are keyed by their hash, and must not be modified after
+func NewAllowlist(queries map[string]string) *Allowlist {
+	return &Allowlist{queries: queries}
+}
+
+func (a *Allowlist) ExtensionName() string {
+	return "PersistedQueryAllowlist"
+}
+
+func (a *Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (a *Allowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	hash := Hash(rawParams.Query)
+	if rawParams.Query == "" {
+		var ok bool
+		if hash, ok = persistedQueryHash(rawParams); !ok {
+			return newError("no persisted query is specified", CodeNotFound)
+		}
+	}
+
+	query, ok := a.queries[hash]
+	switch {
+	case !ok && rawParams.Query == "":
+		return newError("persisted query is not found", CodeNotFound)
+	case !ok:
+		return newError("query is not allowed", CodeNotAllowed)
+	}
+	rawParams.Query = query
+	return nil
+}
+
+// persistedQueryHash returns the hash in the APQ extension of the request
+func persistedQueryHash(rawParams *graphql.RawParams) (string, bool) {
+	ext, ok := rawParams.Extensions["persistedQuery"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	hash, ok := ext["sha256Hash"].(string)
+	return hash, ok
+}
+
+func newError(msg, code string) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    msg,
+		Extensions: map[string]interface{}{"code": code},
+	}
+}
@@ -0,0 +1,91 @@
+package persisted
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Store is the storage of persisted queries
+// it is implemented by db.Queries
+type Store interface {
+	FindPersistedQuery(ctx context.Context, hash string) (string, error)
+	InsertPersistedQuery(ctx context.Context, arg db.InsertPersistedQueryParams) error
+}
+
+// DBCache is a cache of APQ backed by the persisted_queries table
+// failures of the database are treated as cache misses, then clients resend the query
+//
+// queries are added by any client before they are parsed, so they are kept in memory until Persist is called after validation
+// the table has at most maxRows rows, and queries are not persisted when it is full
+type DBCache struct {
+	store   Store
+	maxRows int
+	// pending is queries added by clients which are not validated yet
+	pending *lru.Cache[string, string]
+}
+
+var _ graphql.Cache[string] = (*DBCache)(nil)
+
+// NewDBCache is a constructor for DBCache
+// maxRows is also the number of queries waiting for validation
+func NewDBCache(store Store, maxRows int) (*DBCache, error) {
+	pending, err := lru.New[string, string](maxRows)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size of persisted query cache: %w", err)
+	}
+	return &DBCache{store: store, maxRows: maxRows, pending: pending}, nil
+}
+
+func (c *DBCache) Get(ctx context.Context, key string) (string, bool) {
+	query, err := c.store.FindPersistedQuery(ctx, key)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			slog.WarnContext(ctx, "failed to find persisted query", "hash", key, "error", err.Error())
+		}
+		return "", false
+	}
+	return query, true
+}
+
+// Add keeps the query until it is validated
+func (c *DBCache) Add(_ context.Context, key string, value string) {
+	c.pending.Add(key, value)
+}
+
+// Persist inserts the query into the table if it is added by Add
+// it must be called after the query is validated
+func (c *DBCache) Persist(ctx context.Context, query string) {
+	key := Hash(query)
+	if !c.pending.Remove(key) {
+		return
+	}
+	if err := c.store.InsertPersistedQuery(ctx, db.InsertPersistedQueryParams{Hash: key, Query: query, MaxRows: int64(c.maxRows)}); err != nil {
+		slog.WarnContext(ctx, "failed to insert persisted query", "hash", key, "error", err.Error())
+	}
+}
+
+// dbAPQ is AutomaticPersistedQuery persisting only valid queries into DBCache
+type dbAPQ struct {
+	extension.AutomaticPersistedQuery
+	cache *DBCache
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.OperationContextMutator
+} = dbAPQ{}
+
+// MutateOperationContext persists the query after it is parsed and validated
+func (e dbAPQ) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	e.cache.Persist(ctx, rc.RawQuery)
+	return nil
+}
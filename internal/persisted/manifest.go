@@ -0,0 +1,50 @@
+package persisted
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	manifestFormat  = "apollo-persisted-query-manifest"
+	manifestVersion = 1
+)
+
+// manifest is a persisted query manifest generated by clients
+// https://www.apollographql.com/docs/graphos/operations/persisted-queries#manifest-format
+type manifest struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	Operations []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Body string `json:"body"`
+	} `json:"operations"`
+}
+
+// LoadManifest loads a manifest file and returns queries keyed by their hash
+// ids of operations must be the SHA-256 hash of their body
+func LoadManifest(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if m.Format != manifestFormat || m.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported manifest: format=%q version=%d", m.Format, m.Version)
+	}
+
+	queries := make(map[string]string, len(m.Operations))
+	for _, op := range m.Operations {
+		if Hash(op.Body) != op.ID {
+			return nil, fmt.Errorf("id of operation %q does not match its body", op.Name)
+		}
+		queries[op.ID] = op.Body
+	}
+	return queries, nil
+}
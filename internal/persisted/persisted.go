@@ -0,0 +1,77 @@
+package persisted
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+)
+
+const (
+	// ModeAPQ registers any query sent with its hash (Automatic Persisted Queries)
+	ModeAPQ = "apq"
+	// ModeAllowlist executes only queries registered from a manifest
+	ModeAllowlist = "allowlist"
+
+	// CacheMemory stores APQ in an in-memory LRU cache of each replica
+	CacheMemory = "memory"
+	// CachePostgres stores APQ in the persisted_queries table shared by replicas
+	// only valid queries are stored, and the number of rows is limited by CacheSize
+	CachePostgres = "postgres"
+)
+
+// Config is the configuration of persisted queries
+type Config struct {
+	// Mode is ModeAPQ or ModeAllowlist
+	Mode string
+	// Cache is CacheMemory or CachePostgres, used in ModeAPQ
+	Cache string
+	// CacheSize is the size of the in-memory cache, or the max number of rows of the table
+	CacheSize int
+	// ManifestFile is the path of the manifest, used in ModeAllowlist
+	ManifestFile string
+}
+
+// NewExtension returns a gqlgen extension of the configured mode
+func NewExtension(cnf Config, store Store) (graphql.HandlerExtension, error) {
+	switch cnf.Mode {
+	case ModeAPQ:
+		cache, err := newCache(cnf, store)
+		if err != nil {
+			return nil, err
+		}
+		apq := extension.AutomaticPersistedQuery{Cache: cache}
+		if c, ok := cache.(*DBCache); ok {
+			return dbAPQ{AutomaticPersistedQuery: apq, cache: c}, nil
+		}
+		return apq, nil
+	case ModeAllowlist:
+		queries, err := LoadManifest(cnf.ManifestFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewAllowlist(queries), nil
+	default:
+		return nil, fmt.Errorf("unknown persisted query mode: %q", cnf.Mode)
+	}
+}
+
+func newCache(cnf Config, store Store) (graphql.Cache[string], error) {
+	switch cnf.Cache {
+	case CacheMemory:
+		return lru.New[string](cnf.CacheSize), nil
+	case CachePostgres:
+		return NewDBCache(store, cnf.CacheSize)
+	default:
+		return nil, fmt.Errorf("unknown persisted query cache: %q", cnf.Cache)
+	}
+}
+
+// Hash returns the hex encoded SHA-256 hash of a query, which is the key of persisted queries
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
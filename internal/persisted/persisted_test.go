@@ -0,0 +1,329 @@
+package persisted
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+)
+
+const (
+	viewerQuery = "query Viewer { viewer { id } }"
+	usersQuery  = "query Users { users { edges { node { id } } } }"
+)
+
+func writeManifest(t *testing.T, m manifest) string {
+	t.Helper()
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+	p := path.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(p, b, 0o600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	return p
+}
+
+func newManifest(bodies map[string]string) manifest {
+	m := manifest{Format: manifestFormat, Version: manifestVersion}
+	for id, body := range bodies {
+		m.Operations = append(m.Operations, struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			Type string `json:"type"`
+			Body string `json:"body"`
+		}{ID: id, Name: "Op", Type: "query", Body: body})
+	}
+	return m
+}
+
+func TestLoadManifest(t *testing.T) {
+	testCases := map[string]struct {
+		manifest manifest
+		want     map[string]string
+		wantErr  bool
+	}{
+		"success: operations": {
+			manifest: newManifest(map[string]string{Hash(viewerQuery): viewerQuery}),
+			want:     map[string]string{Hash(viewerQuery): viewerQuery},
+		},
+		"failure: unsupported_format": {
+			manifest: manifest{Format: "relay", Version: manifestVersion},
+			wantErr:  true,
+		},
+		"failure: id_does_not_match_body": {
+			manifest: newManifest(map[string]string{Hash(usersQuery): viewerQuery}),
+			wantErr:  true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := LoadManifest(writeManifest(t, tt.manifest))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected queries: %v", diff)
+			}
+		})
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	a := NewAllowlist(map[string]string{Hash(viewerQuery): viewerQuery})
+
+	testCases := map[string]struct {
+		params    graphql.RawParams
+		wantQuery string
+		wantCode  string
+	}{
+		"success: hash": {
+			params: graphql.RawParams{Extensions: map[string]interface{}{
+				"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": Hash(viewerQuery)},
+			}},
+			wantQuery: viewerQuery,
+		},
+		"success: registered_query": {
+			params:    graphql.RawParams{Query: viewerQuery},
+			wantQuery: viewerQuery,
+		},
+		"failure: unknown_hash": {
+			params: graphql.RawParams{Extensions: map[string]interface{}{
+				"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": Hash(usersQuery)},
+			}},
+			wantCode: CodeNotFound,
+		},
+		"failure: unregistered_query": {
+			params:   graphql.RawParams{Query: usersQuery},
+			wantCode: CodeNotAllowed,
+		},
+		"failure: unregistered_query_with_registered_hash": {
+			params: graphql.RawParams{Query: usersQuery, Extensions: map[string]interface{}{
+				"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": Hash(viewerQuery)},
+			}},
+			wantCode: CodeNotAllowed,
+		},
+		"failure: empty": {
+			params:   graphql.RawParams{},
+			wantCode: CodeNotFound,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			params := tt.params
+			err := a.MutateOperationParameters(context.Background(), &params)
+			if tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("error should be returned")
+				}
+				if diff := cmp.Diff(tt.wantCode, err.Extensions["code"]); diff != "" {
+					t.Errorf("unexpected code: %v", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantQuery, params.Query); diff != "" {
+				t.Errorf("unexpected query: %v", diff)
+			}
+		})
+	}
+}
+
+type fakeStore struct {
+	queries  map[string]string
+	inserted []db.InsertPersistedQueryParams
+	err      error
+}
+
+func (s *fakeStore) FindPersistedQuery(_ context.Context, hash string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	q, ok := s.queries[hash]
+	if !ok {
+		return "", pgx.ErrNoRows
+	}
+	return q, nil
+}
+
+func (s *fakeStore) InsertPersistedQuery(_ context.Context, arg db.InsertPersistedQueryParams) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.inserted = append(s.inserted, arg)
+	s.queries[arg.Hash] = arg.Query
+	return nil
+}
+
+func TestDBCache(t *testing.T) {
+	ctx := context.Background()
+
+	// added queries are not found until they are persisted after validation
+	store := &fakeStore{queries: map[string]string{}}
+	c, err := NewDBCache(store, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Add(ctx, Hash(viewerQuery), viewerQuery)
+	if _, ok := c.Get(ctx, Hash(viewerQuery)); ok {
+		t.Errorf("query should not be found before persisted")
+	}
+	c.Persist(ctx, viewerQuery)
+	got, ok := c.Get(ctx, Hash(viewerQuery))
+	if !ok {
+		t.Fatalf("query should be found")
+	}
+	if diff := cmp.Diff(viewerQuery, got); diff != "" {
+		t.Errorf("unexpected query: %v", diff)
+	}
+
+	// queries are inserted once, and queries not added by clients are not inserted
+	c.Persist(ctx, viewerQuery)
+	c.Persist(ctx, usersQuery)
+	want := []db.InsertPersistedQueryParams{{Hash: Hash(viewerQuery), Query: viewerQuery, MaxRows: 10}}
+	if diff := cmp.Diff(want, store.inserted); diff != "" {
+		t.Errorf("unexpected inserted queries: %v", diff)
+	}
+
+	// failures of the database are cache misses
+	c, err = NewDBCache(&fakeStore{err: errors.New("connection refused")}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Add(ctx, Hash(viewerQuery), viewerQuery)
+	c.Persist(ctx, viewerQuery)
+	if _, ok := c.Get(ctx, Hash(viewerQuery)); ok {
+		t.Errorf("query should not be found")
+	}
+}
+
+func TestNewExtension(t *testing.T) {
+	testCases := map[string]struct {
+		cnf     Config
+		want    string
+		wantErr bool
+	}{
+		"success: apq_memory": {
+			cnf:  Config{Mode: ModeAPQ, Cache: CacheMemory, CacheSize: 10},
+			want: "AutomaticPersistedQuery",
+		},
+		"success: apq_postgres": {
+			cnf:  Config{Mode: ModeAPQ, Cache: CachePostgres, CacheSize: 10},
+			want: "AutomaticPersistedQuery",
+		},
+		"success: allowlist": {
+			cnf:  Config{Mode: ModeAllowlist, ManifestFile: writeManifest(t, newManifest(nil))},
+			want: "PersistedQueryAllowlist",
+		},
+		"failure: unknown_mode": {
+			cnf:     Config{Mode: "none"},
+			wantErr: true,
+		},
+		"failure: unknown_cache": {
+			cnf:     Config{Mode: ModeAPQ, Cache: "redis"},
+			wantErr: true,
+		},
+		"failure: invalid_cache_size": {
+			cnf:     Config{Mode: ModeAPQ, Cache: CachePostgres},
+			wantErr: true,
+		},
+		"failure: manifest_not_found": {
+			cnf:     Config{Mode: ModeAllowlist, ManifestFile: path.Join(t.TempDir(), "missing.json")},
+			wantErr: true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewExtension(tt.cnf, &fakeStore{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got.ExtensionName()); diff != "" {
+				t.Errorf("unexpected extension: %v", diff)
+			}
+		})
+	}
+}
+
+func TestDBAPQ(t *testing.T) {
+	testCases := map[string]struct {
+		query        string
+		wantInserted bool
+	}{
+		"success: valid_query_is_persisted": {
+			query:        "query Typename { __typename }",
+			wantInserted: true,
+		},
+		"failure: invalid_query_is_not_persisted": {
+			query: "query Unknown { unknownField }",
+		},
+		"failure: unparsable_query_is_not_persisted": {
+			query: "query {",
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			store := &fakeStore{queries: map[string]string{}}
+			ext, err := NewExtension(Config{Mode: ModeAPQ, Cache: CachePostgres, CacheSize: 10}, store)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+			srv.AddTransport(transport.POST{})
+			srv.Use(ext)
+
+			body, err := json.Marshal(map[string]interface{}{
+				"query": tt.query,
+				"extensions": map[string]interface{}{
+					"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": Hash(tt.query)},
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to marshal request: %v", err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			srv.ServeHTTP(httptest.NewRecorder(), req)
+
+			_, got := store.queries[Hash(tt.query)]
+			if diff := cmp.Diff(tt.wantInserted, got); diff != "" {
+				t.Errorf("unexpected persisted: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package querylimit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// CostHistogram is a histogram of the complexity of operations
+	CostHistogram = "graphql_query_complexity"
+
+	// CodeTooDeep is the error code of operations exceeding the max depth
+	CodeTooDeep = "QUERY_TOO_DEEP"
+	// CodeTooComplex is the error code of operations exceeding the complexity budget
+	CodeTooComplex = "QUERY_TOO_COMPLEX"
+)
+
+// Config is the configuration of query limits
+type Config struct {
+	// MaxDepth is the maximum depth of selection sets (0 is unlimited)
+	MaxDepth int
+	// MaxComplexity is the maximum total complexity of an operation (0 is unlimited)
+	MaxComplexity int
+	// AllowIntrospection skips __schema and __type subtrees in the depth
+	// it is set when introspection is allowed, because the introspection query of GraphiQL is deeper than usual operations
+	AllowIntrospection bool
+}
+
+// Limit is a gqlgen extension rejecting too deep or too complex operations
+// the cost of each field is given by the ComplexityRoot of the executable schema
+type Limit struct {
+	cnf  Config
+	cost *metrics.Histogram[costLabels]
+	es   graphql.ExecutableSchema
+}
+
+// costLabels is labels of CostHistogram
+type costLabels struct {
+	// Operation is the type of the operation (query, mutation or subscription)
+	Operation string `label:"operation"`
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = (*Limit)(nil)
+
+// RegisterMetrics registers metrics of query limits
+// it must be called before the extension is created
+func RegisterMetrics(m *metrics.Client) {
+	costHistogram(m)
+}
+
+// costHistogram returns the handle of CostHistogram
+func costHistogram(m *metrics.Client) *metrics.Histogram[costLabels] {
+	return metrics.NewHistogram[costLabels](m, CostHistogram, "GraphQLオペレーションの計算コスト", []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000})
+}
+
+// New is a constructor for Limit
+func New(cnf Config, m *metrics.Client) *Limit {
+	return &Limit{cnf: cnf, cost: costHistogram(m)}
+}
+
+func (l *Limit) ExtensionName() string {
+	return "QueryLimit"
+}
+
+func (l *Limit) Validate(schema graphql.ExecutableSchema) error {
+	l.es = schema
+	return nil
+}
+
+// MutateOperationContext checks limits after the operation is parsed and validated
+func (l *Limit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	op := rc.Operation
+
+	depth := depthOf(op.SelectionSet, l.cnf.AllowIntrospection)
+	if l.cnf.MaxDepth > 0 && depth > l.cnf.MaxDepth {
+		return &gqlerror.Error{
+			Message: fmt.Sprintf("operation has depth %d, which exceeds the limit of %d", depth, l.cnf.MaxDepth),
+			Extensions: map[string]interface{}{
+				"code":  CodeTooDeep,
+				"depth": depth,
+				"limit": l.cnf.MaxDepth,
+			},
+		}
+	}
+
+	cost := complexity.Calculate(l.es, op, rc.Variables)
+	l.cost.Observe(costLabels{Operation: string(op.Operation)}, float64(cost))
+	if l.cnf.MaxComplexity > 0 && cost > l.cnf.MaxComplexity {
+		return &gqlerror.Error{
+			Message: fmt.Sprintf("operation has complexity %d, which exceeds the limit of %d", cost, l.cnf.MaxComplexity),
+			Extensions: map[string]interface{}{
+				"code":  CodeTooComplex,
+				"cost":  cost,
+				"limit": l.cnf.MaxComplexity,
+			},
+		}
+	}
+	return nil
+}
+
+// Depth returns the maximum depth of nested fields in the selection set
+// fragments do not add depth, and __typename is not counted
+func Depth(selectionSet ast.SelectionSet) int {
+	return depthOf(selectionSet, false)
+}
+
+// depthOf returns the depth of the selection set, skipping introspection subtrees if skipIntrospection is true
+// introspection subtrees are counted by default, otherwise they bypass the limit while introspection is disabled
+func depthOf(selectionSet ast.SelectionSet, skipIntrospection bool) int {
+	depth := 0
+	for _, selection := range selectionSet {
+		d := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name == "__typename" {
+				continue
+			}
+			if skipIntrospection && (s.Name == "__schema" || s.Name == "__type") {
+				continue
+			}
+			d = 1 + depthOf(s.SelectionSet, skipIntrospection)
+		case *ast.InlineFragment:
+			d = depthOf(s.SelectionSet, skipIntrospection)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = depthOf(s.Definition.SelectionSet, skipIntrospection)
+			}
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
@@ -0,0 +1,127 @@
+package querylimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+	"github.com/vektah/gqlparser/v2"
+)
+
+var testMetrics = func() *metrics.Client {
+	registry := prometheus.NewRegistry()
+	m := metrics.NewClientWithRegistry(registry, registry)
+	RegisterMetrics(m)
+	return m
+}()
+
+func newOperationContext(t *testing.T, es graphql.ExecutableSchema, query string, vars map[string]interface{}) *graphql.OperationContext {
+	t.Helper()
+	doc, errs := gqlparser.LoadQuery(es.Schema(), query)
+	if errs != nil {
+		t.Fatalf("failed to parse query: %v", errs)
+	}
+	return &graphql.OperationContext{
+		RawQuery:  query,
+		Variables: vars,
+		Doc:       doc,
+		Operation: doc.Operations[0],
+	}
+}
+
+func TestLimit(t *testing.T) {
+	es := graph.NewExecutableSchema(graph.Config{
+		Resolvers:  &graph.Resolver{},
+		Complexity: graph.NewComplexityRoot(),
+	})
+
+	testCases := map[string]struct {
+		cnf   Config
+		query string
+		vars  map[string]interface{}
+		want  map[string]interface{}
+	}{
+		"success: within_limits": {
+			cnf:   Config{MaxDepth: 4, MaxComplexity: 100},
+			query: `query { users(first: 10) { edges { node { id name } } } }`,
+		},
+		"success: unlimited": {
+			cnf:   Config{},
+			query: `query { users(first: 100) { edges { node { id name } } } }`,
+		},
+		"success: fragments_do_not_add_depth": {
+			cnf: Config{MaxDepth: 4},
+			query: `
+			query { users { edges { node { ...UserFields } } } }
+			fragment UserFields on User { ... on User { id } }
+			`,
+		},
+		"success: typename_is_not_counted": {
+			cnf:   Config{MaxDepth: 1},
+			query: `query { viewer { __typename } }`,
+		},
+		"success: introspection_is_skipped_when_allowed": {
+			cnf:   Config{MaxDepth: 2, AllowIntrospection: true},
+			query: `query { __schema { types { fields { type { name } } } } viewer { id } }`,
+		},
+		"failure: too_deep": {
+			cnf:   Config{MaxDepth: 3},
+			query: `query { users { edges { node { id } } } }`,
+			want:  map[string]interface{}{"code": CodeTooDeep, "depth": 4, "limit": 3},
+		},
+		"failure: introspection_is_counted": {
+			cnf:   Config{MaxDepth: 2},
+			query: `query { __schema { types { fields { type { name } } } } viewer { id } }`,
+			want:  map[string]interface{}{"code": CodeTooDeep, "depth": 5, "limit": 2},
+		},
+		"failure: connection_multiplies_children": {
+			// users(1 + 50 * edges(1 + node(1 + id(1) + name(1))))
+			cnf:   Config{MaxComplexity: 150},
+			query: `query { users(first: 50) { edges { node { id name } } } }`,
+			want:  map[string]interface{}{"code": CodeTooComplex, "cost": 201, "limit": 150},
+		},
+		"failure: page_size_from_variables": {
+			cnf:   Config{MaxComplexity: 100},
+			query: `query ($last: Int) { users(last: $last) { edges { cursor } } }`,
+			vars:  map[string]interface{}{"last": 60},
+			want:  map[string]interface{}{"code": CodeTooComplex, "cost": 121, "limit": 100},
+		},
+		"failure: nodes_by_ids": {
+			// nodes(1 + 3 * id(1))
+			cnf:   Config{MaxComplexity: 3},
+			query: `query { nodes(ids: ["a", "b", "c"]) { id } }`,
+			want:  map[string]interface{}{"code": CodeTooComplex, "cost": 4, "limit": 3},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			l := New(tt.cnf, testMetrics)
+			if err := l.Validate(es); err != nil {
+				t.Fatalf("failed to validate: %v", err)
+			}
+			rc := newOperationContext(t, es, tt.query, tt.vars)
+
+			got := l.MutateOperationContext(context.Background(), rc)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("unexpected error: %v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("error should be returned")
+			}
+			if diff := cmp.Diff(tt.want, got.Extensions); diff != "" {
+				t.Errorf("unexpected extensions: %v", diff)
+			}
+		})
+	}
+}
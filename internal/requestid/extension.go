@@ -0,0 +1,45 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/cachecontrol"
+)
+
+// ExtensionKey is the key of the request ID in `extensions` of GraphQL responses
+const ExtensionKey = "requestId"
+
+// Extension is a gqlgen extension returning the request ID in `extensions`
+// it must be used before cachecontrol.Extension to see the final cache policy
+type Extension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = Extension{}
+
+func (Extension) ExtensionName() string {
+	return "RequestID"
+}
+
+func (Extension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	id := FromContext(ctx)
+	if resp == nil || id == "" {
+		return resp
+	}
+	// cacheable responses are shared by clients and revalidated by ETag, so they must not contain the request ID
+	if p := cachecontrol.FromContext(ctx); p != nil && p.MaxAge() > 0 {
+		return resp
+	}
+	if resp.Extensions == nil {
+		resp.Extensions = make(map[string]interface{})
+	}
+	resp.Extensions[ExtensionKey] = id
+	return resp
+}
@@ -0,0 +1,35 @@
+package requestid
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogKey is the key of the request ID in log records
+const LogKey = "request_id"
+
+// LogHandler is a slog.Handler adding the request ID of the context to records
+// logs must be written with context (ex. slog.ErrorContext) to be correlated
+type LogHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler is a constructor for LogHandler
+func NewLogHandler(h slog.Handler) *LogHandler {
+	return &LogHandler{Handler: h}
+}
+
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		r.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewLogHandler(h.Handler.WithAttrs(attrs))
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return NewLogHandler(h.Handler.WithGroup(name))
+}
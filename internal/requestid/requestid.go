@@ -0,0 +1,46 @@
+package requestid
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Header is the header carrying the request ID
+const Header = echo.HeaderXRequestID
+
+// validID is the format of request IDs accepted from clients
+// IDs are embedded into logs and SQL comments, so other characters are rejected
+var validID = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+type ctxKey struct{}
+
+// NewContext returns a context with the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID, or "" if ctx is not of a request
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Middleware stores the request ID into the request context and the response header
+// X-Request-ID of the request is honored if it is valid, otherwise a new ID is generated
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(Header)
+			if !validID.MatchString(id) {
+				id = uuid.NewString()
+			}
+			c.Response().Header().Set(Header, id)
+			c.SetRequest(req.WithContext(NewContext(req.Context(), id)))
+			return next(c)
+		}
+	}
+}
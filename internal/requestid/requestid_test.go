@@ -0,0 +1,124 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+func TestMiddleware(t *testing.T) {
+	testCases := map[string]struct {
+		header    string
+		wantHonor bool
+	}{
+		"success: honor_header": {
+			header:    "abc-123_def.456",
+			wantHonor: true,
+		},
+		"success: generate_without_header": {
+			header: "",
+		},
+		"success: generate_for_invalid_header": {
+			header: "*/ DROP TABLE users; /*",
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			var got string
+			e.GET("/", func(c echo.Context) error {
+				got = FromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}, Middleware())
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if !validID.MatchString(got) {
+				t.Fatalf("invalid request id: %q", got)
+			}
+			if diff := cmp.Diff(got, rec.Header().Get(Header)); diff != "" {
+				t.Errorf("unexpected response header: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantHonor, got == tt.header); diff != "" {
+				t.Errorf("unexpected request id %q: %v", got, diff)
+			}
+		})
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+	logger.InfoContext(NewContext(context.Background(), "req-1"), "with request")
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal log: %v", err)
+	}
+	if diff := cmp.Diff("req-1", got[LogKey]); diff != "" {
+		t.Errorf("unexpected request id: %v", diff)
+	}
+
+	// logs out of requests have no request ID
+	buf.Reset()
+	logger.Info("without request")
+	got = nil
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal log: %v", err)
+	}
+	if _, ok := got[LogKey]; ok {
+		t.Errorf("request id should not be logged: %v", got)
+	}
+}
+
+func TestWithComment(t *testing.T) {
+	const sql = "/* users_001 */ SELECT * FROM users WHERE id = $1"
+
+	testCases := map[string]struct {
+		ctx      context.Context
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		"success: with_request_id": {
+			ctx:      NewContext(context.Background(), "req-1"),
+			wantSQL:  sql + " /* request_id=req-1 */",
+			wantArgs: []interface{}{pgx.QueryExecModeDescribeExec, "id"},
+		},
+		"success: without_request_id": {
+			ctx:      context.Background(),
+			wantSQL:  sql,
+			wantArgs: []interface{}{"id"},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			gotSQL, gotArgs := withComment(tt.ctx, sql, []interface{}{"id"})
+			if diff := cmp.Diff(tt.wantSQL, gotSQL); diff != "" {
+				t.Errorf("unexpected sql: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantArgs, gotArgs); diff != "" {
+				t.Errorf("unexpected args: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+)
+
+// DBTX appends the request ID to SQL as a comment (ex. /* users_001 */ ... /* request_id=... */)
+// so that queries in pg_stat_activity and Postgres logs are correlated with requests
+//
+// SQL differs in each request, so queries in requests are executed with QueryExecModeDescribeExec
+// it costs an extra round trip per query instead of the prepared statement cache, so use it only when the correlation is needed
+type DBTX struct {
+	db db.DBTX
+}
+
+var _ db.DBTX = (*DBTX)(nil)
+
+// NewDBTX is a constructor for DBTX
+func NewDBTX(dbtx db.DBTX) *DBTX {
+	return &DBTX{db: dbtx}
+}
+
+func (d *DBTX) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	sql, args = withComment(ctx, sql, args)
+	return d.db.Exec(ctx, sql, args...)
+}
+
+func (d *DBTX) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	sql, args = withComment(ctx, sql, args)
+	return d.db.Query(ctx, sql, args...)
+}
+
+func (d *DBTX) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	sql, args = withComment(ctx, sql, args)
+	return d.db.QueryRow(ctx, sql, args...)
+}
+
+// withComment appends the request ID of ctx to sql
+// each request makes different SQL, so it is executed without the statement cache not to evict cached statements
+func withComment(ctx context.Context, sql string, args []interface{}) (string, []interface{}) {
+	id := FromContext(ctx)
+	if id == "" {
+		return sql, args
+	}
+	return sql + " /* request_id=" + id + " */", append([]interface{}{pgx.QueryExecModeDescribeExec}, args...)
+}
@@ -5,29 +5,70 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/apperr"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/cachecontrol"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/health"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/requestid"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
+const (
+	// PlaygroundGraphiQL serves GraphiQL
+	PlaygroundGraphiQL = "graphiql"
+	// PlaygroundSandbox serves Apollo Sandbox
+	PlaygroundSandbox = "sandbox"
+	// PlaygroundNone serves no playground
+	PlaygroundNone = "none"
+)
+
+// PlaygroundConfig is the configuration of the playground page
+type PlaygroundConfig struct {
+	// Kind is PlaygroundGraphiQL, PlaygroundSandbox or PlaygroundNone
+	Kind string
+	// Path is the path of the page
+	Path string
+}
+
 type Server struct {
-	port       string
-	gqlHandler handler.Server
-	server     *echo.Echo
+	port          string
+	gqlHandler    handler.Server
+	healthHandler *health.Handler
+	verifier      *auth.Verifier
+	metrics       *metrics.Client
+	server        *echo.Echo
 }
 
-func NewServer(port int, gqlHandler handler.Server) *Server {
+func NewServer(port int, gqlHandler handler.Server, healthHandler *health.Handler, verifier *auth.Verifier, m *metrics.Client) *Server {
 	return &Server{
-		port:       fmt.Sprintf(":%d", port),
-		gqlHandler: gqlHandler,
-		server:     echo.New(),
+		port:          fmt.Sprintf(":%d", port),
+		gqlHandler:    gqlHandler,
+		healthHandler: healthHandler,
+		verifier:      verifier,
+		metrics:       m,
+		server:        echo.New(),
 	}
 }
 
-func (s *Server) Start(hasPlayground bool) error {
+func (s *Server) Start(pg PlaygroundConfig) error {
+	playgroundHandler, err := newPlaygroundHandler(pg.Kind, "/graphql")
+	if err != nil {
+		return err
+	}
+	// the request ID is assigned first to be shown in the access log
+	s.server.Use(requestid.Middleware())
+	s.server.Use(otelecho.Middleware("api", otelecho.WithSkipper(func(c echo.Context) bool {
+		// ignore health check, metrics
+		return strings.Contains(c.Path(), "health") || strings.Contains(c.Path(), "metrics")
+	})))
 	s.server.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
 		Skipper: func(c echo.Context) bool {
 			// ignore health check, metrics
@@ -39,24 +80,36 @@ func (s *Server) Start(hasPlayground bool) error {
 	}))
 	s.server.Use(middleware.Recover())
 	// GraphQL
-	s.server.POST("/graphql", func(c echo.Context) error {
+	gqlHandler := func(c echo.Context) error {
 		s.gqlHandler.ServeHTTP(c.Response(), c.Request())
 		return nil
-	})
+	}
+	s.server.POST("/graphql", gqlHandler, authenticate(s.verifier))
+	/// GET accepts only queries, and responses are cacheable by @cacheControl
+	/// WebSocket (graphql-ws, graphql-transport-ws) is also upgraded from GET
+	s.server.GET("/graphql", gqlHandler, authenticate(s.verifier), cachecontrol.Middleware())
+	// health check
+	s.server.GET("/health/live", s.healthHandler.Live)
+	s.server.GET("/health/ready", s.healthHandler.Ready)
 	// metrics
+	/// metrics of requests are registered to the registry of the client, and /metrics serves the registry
 	mwConf := echoprometheus.MiddlewareConfig{
-		Subsystem: "api",
+		Subsystem:  "api",
+		Registerer: s.metrics.Registerer(),
 		Skipper: func(c echo.Context) bool {
 			// ignore health check, metrics
 			return strings.Contains(c.Path(), "health") || strings.Contains(c.Path(), "metrics")
 		},
 	}
-	s.server.Use(echoprometheus.NewMiddlewareWithConfig(mwConf))
-	s.server.GET("/metrics", echoprometheus.NewHandler())
+	metricsMiddleware, err := mwConf.ToMiddleware()
+	if err != nil {
+		return err
+	}
+	s.server.Use(metricsMiddleware)
+	s.server.GET("/metrics", echo.WrapHandler(s.metrics.Handler()))
 
-	if hasPlayground {
-		playgroundHandler := playground.Handler("GraphQL playground", "/graphql")
-		s.server.GET("/", func(c echo.Context) error {
+	if playgroundHandler != nil {
+		s.server.GET(pg.Path, func(c echo.Context) error {
 			playgroundHandler.ServeHTTP(c.Response(), c.Request())
 			return nil
 		})
@@ -65,7 +118,74 @@ func (s *Server) Start(hasPlayground bool) error {
 	return s.server.Start(s.port)
 }
 
-func (s *Server) Shutdown(ctx context.Context) error {
+// newPlaygroundHandler returns a handler of the playground page, or nil if no playground is served
+func newPlaygroundHandler(kind string, endpoint string) (http.HandlerFunc, error) {
+	switch kind {
+	case PlaygroundGraphiQL:
+		return playground.Handler("GraphQL playground", endpoint), nil
+	case PlaygroundSandbox:
+		return playground.ApolloSandboxHandler("GraphQL playground", endpoint), nil
+	case PlaygroundNone, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown playground: %q", kind)
+	}
+}
+
+// authenticate verifies a bearer token and stores the principal into the request context
+// requests without Authorization header are processed as anonymous
+func authenticate(v *auth.Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return next(c)
+			}
+
+			token, ok := bearerToken(header)
+			if !ok {
+				return unauthorized(c, "authorization header must be a bearer token")
+			}
+			p, err := v.Verify(token)
+			if err != nil {
+				return unauthorized(c, "invalid token")
+			}
+
+			c.SetRequest(c.Request().WithContext(auth.NewContext(c.Request().Context(), p)))
+			return next(c)
+		}
+	}
+}
+
+// bearerToken extracts a token from the value of Authorization header
+func bearerToken(header string) (string, bool) {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return header[len(prefix):], true
+}
+
+// unauthorized responds 401 in the format of GraphQL errors
+func unauthorized(c echo.Context, message string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+	return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"message":    message,
+			"extensions": map[string]interface{}{"code": apperr.CodeUnauthenticated},
+		}},
+	})
+}
+
+// Shutdown stops the server gracefully
+// requests are served for drainDelay after readiness fails, so that load balancers notice it before the listener is closed
+func (s *Server) Shutdown(ctx context.Context, drainDelay time.Duration) error {
+	// stop receiving new requests from load balancers first
+	s.healthHandler.Shutdown()
+	select {
+	case <-time.After(drainDelay):
+	case <-ctx.Done():
+	}
 	return s.server.Shutdown(ctx)
 }
 
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/health"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+)
+
+func TestShutdownDrainDelay(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	registry := prometheus.NewRegistry()
+	s := NewServer(port, handler.Server{}, health.NewHandler(), nil, metrics.NewClientWithRegistry(registry, registry))
+	go func() {
+		_ = s.Start(PlaygroundConfig{Kind: PlaygroundNone})
+	}()
+
+	ready := func() (int, error) {
+		res, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/health/ready", port))
+		if err != nil {
+			return 0, err
+		}
+		defer res.Body.Close()
+		return res.StatusCode, nil
+	}
+	for i := 0; ; i++ {
+		if _, err := ready(); err == nil {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("server is not started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Shutdown(context.Background(), 500*time.Millisecond)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// readiness fails while the server keeps serving
+	status, err := ready()
+	if err != nil {
+		t.Fatalf("server should serve during the drain delay: %v", err)
+	}
+	if diff := cmp.Diff(http.StatusServiceUnavailable, status); diff != "" {
+		t.Errorf("unexpected status: %v", diff)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("failed to shutdown: %v", err)
+	}
+	if _, err := ready(); err == nil {
+		t.Errorf("server should be closed after the drain delay")
+	}
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// FieldPathKey is the attribute of the path of a resolved field (ex. users.edges.0.node)
+	FieldPathKey = attribute.Key("graphql.field.path")
+	// FieldNameKey is the attribute of the name of a resolved field
+	FieldNameKey = attribute.Key("graphql.field.name")
+)
+
+// Extension is a gqlgen extension creating spans of operations and resolvers
+// fields without resolvers are only read from structs, so they have no spans
+type Extension struct {
+	tracer trace.Tracer
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = (*Extension)(nil)
+
+// NewExtension is a constructor for Extension
+func NewExtension(tp trace.TracerProvider) *Extension {
+	return &Extension{tracer: tp.Tracer(instrumentationName)}
+}
+
+func (e *Extension) ExtensionName() string {
+	return "Tracing"
+}
+
+func (e *Extension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse creates a span of the operation
+// each response of subscriptions has its own span
+func (e *Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	oc := graphql.GetOperationContext(ctx)
+	name, opType := oc.OperationName, "query"
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+		if name == "" {
+			name = oc.Operation.Name
+		}
+	}
+	spanName := opType
+	if name != "" {
+		spanName = fmt.Sprintf("%s %s", opType, name)
+	}
+
+	ctx, span := e.tracer.Start(ctx, spanName,
+		trace.WithAttributes(
+			semconv.GraphqlOperationName(name),
+			semconv.GraphqlOperationTypeKey.String(opType),
+		),
+	)
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+// InterceptField creates a span of the resolver
+func (e *Extension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := e.tracer.Start(ctx, fmt.Sprintf("%s.%s", fc.Object, fc.Field.Name),
+		trace.WithAttributes(
+			FieldPathKey.String(fc.Path().String()),
+			FieldNameKey.String(fc.Field.Name),
+		),
+	)
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
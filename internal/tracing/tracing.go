@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	// ExporterNone exports no spans, trace IDs are still propagated
+	ExporterNone = "none"
+	// ExporterStdout writes spans to stdout, which is useful in development
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends spans by OTLP/HTTP, configured by OTEL_EXPORTER_OTLP_* variables
+	ExporterOTLP = "otlp"
+
+	// instrumentationName is the name of tracers of this application
+	instrumentationName = "github.com/rikeda71/go-gql-sqlc-template/internal/tracing"
+)
+
+// Config is the configuration of tracing
+type Config struct {
+	// Exporter is ExporterNone, ExporterStdout or ExporterOTLP
+	Exporter string
+	// ServiceName is service.name of the resource
+	ServiceName string
+	// SampleRatio is the ratio of sampled root spans, spans follow the parent if it is sampled remotely
+	SampleRatio float64
+}
+
+// NewTracerProvider returns a provider exporting spans to the configured exporter
+// it must be shut down to flush spans
+func NewTracerProvider(ctx context.Context, cnf Config) (*sdktrace.TracerProvider, error) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cnf.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cnf.SampleRatio))),
+	}
+	switch cnf.Exporter {
+	case ExporterNone:
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case ExporterOTLP:
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %q", cnf.Exporter)
+	}
+	return sdktrace.NewTracerProvider(opts...), nil
+}
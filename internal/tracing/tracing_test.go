@@ -0,0 +1,171 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func newTestProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exp := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)), exp
+}
+
+// attributeOf returns the value of the attribute, or "" if the span does not have it
+func attributeOf(span tracetest.SpanStub, key attribute.Key) string {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	testCases := map[string]struct {
+		exporter string
+		wantErr  bool
+	}{
+		"success: none": {
+			exporter: ExporterNone,
+		},
+		"success: stdout": {
+			exporter: ExporterStdout,
+		},
+		"success: otlp": {
+			exporter: ExporterOTLP,
+		},
+		"failure: unknown_exporter": {
+			exporter: "jaeger",
+			wantErr:  true,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			tp, err := NewTracerProvider(context.Background(), Config{Exporter: tt.exporter, ServiceName: "test", SampleRatio: 1})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tp != nil {
+				_ = tp.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	testCases := map[string]struct {
+		sql  string
+		want string
+	}{
+		"success: sqlc": {
+			sql:  "-- name: FindUserByID :one\nSELECT /* users_002 */ id FROM users",
+			want: "FindUserByID",
+		},
+		"success: not_sqlc": {
+			sql:  `listen "user_created"`,
+			want: "LISTEN",
+		},
+		"success: empty": {
+			sql:  "",
+			want: "query",
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, QueryName(tt.sql)); diff != "" {
+				t.Errorf("unexpected name: %v", diff)
+			}
+		})
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	tp, exp := newTestProvider()
+	tracer := NewQueryTracer(tp)
+	const sql = "-- name: FindUserByID :one\nSELECT /* users_002 */ id FROM users WHERE id = $1"
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("unexpected spans: %v", spans)
+	}
+	if diff := cmp.Diff("FindUserByID", spans[0].Name); diff != "" {
+		t.Errorf("unexpected name: %v", diff)
+	}
+	if diff := cmp.Diff("postgresql", attributeOf(spans[0], semconv.DBSystemKey)); diff != "" {
+		t.Errorf("unexpected db.system: %v", diff)
+	}
+	if diff := cmp.Diff(sql, attributeOf(spans[0], semconv.DBQueryTextKey)); diff != "" {
+		t.Errorf("unexpected db.query.text: %v", diff)
+	}
+	if diff := cmp.Diff(codes.Error, spans[0].Status.Code); diff != "" {
+		t.Errorf("unexpected status: %v", diff)
+	}
+}
+
+func TestExtension(t *testing.T) {
+	tp, exp := newTestProvider()
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(NewExtension(tp))
+
+	// the resolver fails before reaching the database
+	body := `{"query": "query Users { users(first: -1) { edges { node { id } } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exp.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("unexpected spans: %v", spans)
+	}
+	// spans are exported when they end, so the resolver comes first
+	field, operation := spans[0], spans[1]
+
+	if diff := cmp.Diff("query Users", operation.Name); diff != "" {
+		t.Errorf("unexpected operation name: %v", diff)
+	}
+	if diff := cmp.Diff("Users", attributeOf(operation, semconv.GraphqlOperationNameKey)); diff != "" {
+		t.Errorf("unexpected graphql.operation.name: %v", diff)
+	}
+	if diff := cmp.Diff(codes.Error, operation.Status.Code); diff != "" {
+		t.Errorf("unexpected operation status: %v", diff)
+	}
+
+	if diff := cmp.Diff("Query.users", field.Name); diff != "" {
+		t.Errorf("unexpected field name: %v", diff)
+	}
+	if diff := cmp.Diff("users", attributeOf(field, FieldPathKey)); diff != "" {
+		t.Errorf("unexpected graphql.field.path: %v", diff)
+	}
+	if diff := cmp.Diff(operation.SpanContext.SpanID(), field.Parent.SpanID()); diff != "" {
+		t.Errorf("resolver span should be a child of the operation span: %v", diff)
+	}
+	if len(field.Events) == 0 {
+		t.Errorf("error should be recorded: %v", field.Events)
+	}
+}
@@ -0,0 +1,334 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+type viewerQueryResponse struct {
+	graphQLErrorsResponse
+	Data struct {
+		Viewer *graph.User `json:"viewer"`
+	} `json:"data"`
+}
+
+func TestViewer(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createUserMutation := api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "viewer", email: "viewer@example.com"}) {
+			metadata {
+				user {
+					id
+					databaseId
+				}
+			}
+		}
+	}
+	`)
+	resBytes, err := api.PostGraphQLRequest(createUserMutation, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var created createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &created); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	user := created.Data.CreateUserOutput.Metadata.User
+
+	viewerQuery := api.NewQuery(`
+	query Viewer {
+		viewer {
+			id
+			name
+		}
+	}
+	`)
+
+	t.Run("success: authenticated", func(t *testing.T) {
+		header, err := api.BearerHeader(TokenSecret, user.DatabaseID, auth.RoleUser)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+
+		// when
+		resBytes, err := api.PostGraphQLRequestWithHeader(viewerQuery, Server, header)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+
+		// then
+		var actual viewerQueryResponse
+		if err := json.Unmarshal(resBytes, &actual); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		want := &graph.User{ID: user.ID, Name: "viewer"}
+		if diff := cmp.Diff(want, actual.Data.Viewer); diff != "" {
+			t.Errorf("unexpected viewer: %v", diff)
+		}
+	})
+
+	t.Run("failure: anonymous", func(t *testing.T) {
+		// when
+		resBytes, err := api.PostGraphQLRequest(viewerQuery, Server)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+
+		// then
+		var actual viewerQueryResponse
+		if err := json.Unmarshal(resBytes, &actual); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if len(actual.Errors) != 1 || actual.Errors[0].Extensions.Code != "UNAUTHENTICATED" {
+			t.Errorf("unexpected errors: %s", resBytes)
+		}
+	})
+
+	t.Run("failure: invalid_token", func(t *testing.T) {
+		header, err := api.BearerHeader("wrong-secret", user.DatabaseID, auth.RoleUser)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+
+		// when
+		req := httptest.NewRequest(echo.POST, "/graphql", viewerQuery.RequestBody())
+		req.Header = header
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		Server.ServeHTTP(rec, req)
+
+		// then
+		if diff := cmp.Diff(http.StatusUnauthorized, rec.Code); diff != "" {
+			t.Errorf("unexpected response code: %v", diff)
+		}
+	})
+}
+
+func TestEmailVisibility(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createUserMutation := api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "email_owner", email: "email_owner@example.com"}) {
+			metadata {
+				user {
+					id
+					databaseId
+				}
+			}
+		}
+	}
+	`)
+	resBytes, err := api.PostGraphQLRequest(createUserMutation, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var created createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &created); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	user := created.Data.CreateUserOutput.Metadata.User
+	userQuery := api.NewQuery(fmt.Sprintf(`
+	query User {
+		user(id: "%s") {
+			name
+			email
+		}
+	}
+	`, user.DatabaseID))
+
+	testCases := map[string]struct {
+		userID    string
+		role      auth.Role
+		wantEmail bool
+	}{
+		"visible: owner": {
+			userID:    user.DatabaseID,
+			role:      auth.RoleUser,
+			wantEmail: true,
+		},
+		"visible: admin": {
+			userID:    "admin",
+			role:      auth.RoleAdmin,
+			wantEmail: true,
+		},
+		"forbidden: other_user": {
+			userID:    "other",
+			role:      auth.RoleUser,
+			wantEmail: false,
+		},
+		"forbidden: anonymous": {
+			wantEmail: false,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			header := http.Header{}
+			if tt.userID != "" {
+				h, err := api.BearerHeader(TokenSecret, tt.userID, tt.role)
+				if err != nil {
+					t.Fatalf("failed to sign token: %v", err)
+				}
+				header = h
+			}
+			resBytes, err := api.PostGraphQLRequestWithHeader(userQuery, Server, header)
+			if err != nil {
+				t.Fatalf("cause error when post graphql request. error = %v", err)
+			}
+
+			// then
+			/// only email is nulled
+			var actual struct {
+				graphQLErrorsResponse
+				Data struct {
+					User *graph.User `json:"user"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(resBytes, &actual); err != nil {
+				t.Fatalf("cause error when unmarshal response. error = %v", err)
+			}
+			if actual.Data.User == nil || actual.Data.User.Name != "email_owner" {
+				t.Fatalf("unexpected user: %s", resBytes)
+			}
+			if tt.wantEmail {
+				if actual.Data.User.Email == nil || len(actual.Errors) != 0 {
+					t.Errorf("email should be visible: %s", resBytes)
+				}
+				return
+			}
+			if actual.Data.User.Email != nil {
+				t.Errorf("email should be null: %s", resBytes)
+			}
+			if len(actual.Errors) != 1 || actual.Errors[0].Extensions.Code != "FORBIDDEN" {
+				t.Errorf("unexpected errors: %s", resBytes)
+			}
+		})
+	}
+}
+
+func TestUserMutationAuthorization(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createUserMutation := api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "mutation_owner", email: "mutation_owner@example.com"}) {
+			metadata {
+				user {
+					id
+					databaseId
+				}
+			}
+		}
+	}
+	`)
+	resBytes, err := api.PostGraphQLRequest(createUserMutation, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var created createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &created); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	user := created.Data.CreateUserOutput.Metadata.User
+
+	mutations := map[string]string{
+		"updateUser":  fmt.Sprintf(`updateUser(input: {id: "%s", name: "mutation_hijacked"}) { status }`, user.ID),
+		"deleteUser":  fmt.Sprintf(`deleteUser(input: {id: "%s"}) { status }`, user.ID),
+		"restoreUser": fmt.Sprintf(`restoreUser(input: {id: "%s"}) { status }`, user.ID),
+	}
+	testCases := map[string]struct {
+		userID   string
+		role     auth.Role
+		wantCode string
+	}{
+		"unauthenticated: anonymous": {
+			wantCode: "UNAUTHENTICATED",
+		},
+		"forbidden: other_user": {
+			userID:   "other",
+			role:     auth.RoleUser,
+			wantCode: "FORBIDDEN",
+		},
+	}
+
+	for tc, tt := range testCases {
+		for name, mutation := range mutations {
+			tt, name, mutation := tt, name, mutation
+			// subtests are not parallel, so that the user is checked after all of them
+			t.Run(tc+"/"+name, func(t *testing.T) {
+				// when
+				header := http.Header{}
+				if tt.userID != "" {
+					h, err := api.BearerHeader(TokenSecret, tt.userID, tt.role)
+					if err != nil {
+						t.Fatalf("failed to sign token: %v", err)
+					}
+					header = h
+				}
+				resBytes, err := api.PostGraphQLRequestWithHeader(api.NewQuery("mutation { "+mutation+" }"), Server, header)
+				if err != nil {
+					t.Fatalf("cause error when post graphql request. error = %v", err)
+				}
+
+				// then
+				var actual struct {
+					graphQLErrorsResponse
+					Data map[string]interface{} `json:"data"`
+				}
+				if err := json.Unmarshal(resBytes, &actual); err != nil {
+					t.Fatalf("cause error when unmarshal response. error = %v", err)
+				}
+				if actual.Data[name] != nil {
+					t.Errorf("mutation should not be executed: %s", resBytes)
+				}
+				if len(actual.Errors) != 1 || actual.Errors[0].Extensions.Code != tt.wantCode {
+					t.Errorf("unexpected errors: %s", resBytes)
+				}
+			})
+		}
+	}
+
+	// the user is not changed by unauthorized mutations
+	userQuery := api.NewQuery(fmt.Sprintf(`
+	query User {
+		user(id: "%s") {
+			name
+		}
+	}
+	`, user.DatabaseID))
+	resBytes, err = api.PostGraphQLRequest(userQuery, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var found userQueryResponse
+	if err := json.Unmarshal(resBytes, &found); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	if diff := cmp.Diff("mutation_owner", found.Data.User.Name); diff != "" {
+		t.Errorf("user should not be changed: %v", diff)
+	}
+}
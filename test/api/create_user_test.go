@@ -0,0 +1,230 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+func TestCreateUserErrors(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	/// a user which conflicts with following inputs
+	existingUserMutation := api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "conflict", email: "conflict@example.com"}) {
+			status
+		}
+	}
+	`)
+	if _, err := api.PostGraphQLRequest(existingUserMutation, Server); err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	testCases := map[string]struct {
+		name         string
+		email        string
+		wantStatus   graph.MutationStatus
+		wantField    string
+		wantProblems []*graph.Problem
+	}{
+		"already_exists: name": {
+			name:         "conflict",
+			email:        "another@example.com",
+			wantStatus:   graph.MutationStatusAlreadyExists,
+			wantField:    "input.name",
+			wantProblems: []*graph.Problem{},
+		},
+		"already_exists: email": {
+			name:         "another",
+			email:        "conflict@example.com",
+			wantStatus:   graph.MutationStatusAlreadyExists,
+			wantField:    "input.email",
+			wantProblems: []*graph.Problem{},
+		},
+		"validation_error: empty_name": {
+			name:       "",
+			email:      "empty@example.com",
+			wantStatus: graph.MutationStatusValidationError,
+			wantField:  "input.name",
+			wantProblems: []*graph.Problem{
+				{Field: "input.name", Code: graph.ProblemCodeTooShort},
+			},
+		},
+		"validation_error: blank_name": {
+			name:       "   ",
+			email:      "blank@example.com",
+			wantStatus: graph.MutationStatusValidationError,
+			wantField:  "input.name",
+			wantProblems: []*graph.Problem{
+				{Field: "input.name", Code: graph.ProblemCodePatternMismatch},
+			},
+		},
+		"validation_error: too_long_name": {
+			name:       strings.Repeat("a", 51),
+			email:      "long@example.com",
+			wantStatus: graph.MutationStatusValidationError,
+			wantField:  "input.name",
+			wantProblems: []*graph.Problem{
+				{Field: "input.name", Code: graph.ProblemCodeTooLong},
+			},
+		},
+		"validation_error: too_long_email": {
+			name:       "long_email",
+			email:      "long@" + strings.Repeat("a", 50) + "." + strings.Repeat("b", 50) + ".com",
+			wantStatus: graph.MutationStatusValidationError,
+			wantField:  "input.email",
+			wantProblems: []*graph.Problem{
+				{Field: "input.email", Code: graph.ProblemCodeTooLong},
+			},
+		},
+		"validation_error: multiple_problems": {
+			name:       "",
+			email:      "long@" + strings.Repeat("a", 50) + "." + strings.Repeat("b", 50) + ".com",
+			wantStatus: graph.MutationStatusValidationError,
+			wantField:  "input.name",
+			wantProblems: []*graph.Problem{
+				{Field: "input.name", Code: graph.ProblemCodeTooShort},
+				{Field: "input.email", Code: graph.ProblemCodeTooLong},
+			},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			// when
+			mutation := api.NewQuery(fmt.Sprintf(`
+			mutation CreateUser {
+				createUser(input: {name: "%s", email: "%s"}) {
+					status
+					errorMessage
+					errorField
+					problems {
+						field
+						code
+					}
+				}
+			}
+			`, tt.name, tt.email))
+			resBytes, err := api.PostGraphQLRequest(mutation, Server)
+			if err != nil {
+				t.Fatalf("cause error when post graphql request. error = %v", err)
+			}
+
+			// then
+			var actual createUserMutationResponse
+			if err := json.Unmarshal(resBytes, &actual); err != nil {
+				t.Fatalf("cause error when unmarshal response. error = %v", err)
+			}
+			got := actual.Data.CreateUserOutput
+			if diff := cmp.Diff(tt.wantStatus, got.Status); diff != "" {
+				t.Errorf("unexpected status: %v", diff)
+			}
+			if got.ErrorField == nil {
+				t.Fatalf("errorField is empty")
+			}
+			if diff := cmp.Diff(tt.wantField, *got.ErrorField); diff != "" {
+				t.Errorf("unexpected errorField: %v", diff)
+			}
+			if got.ErrorMessage == nil || strings.Contains(*got.ErrorMessage, "SQLSTATE") {
+				t.Errorf("unexpected errorMessage: %v", got.ErrorMessage)
+			}
+			if diff := cmp.Diff(tt.wantProblems, got.Problems); diff != "" {
+				t.Errorf("unexpected problems: %v", diff)
+			}
+		})
+	}
+}
+
+func TestCreateUserEmailScalar(t *testing.T) {
+
+	t.Parallel()
+
+	testCases := map[string]struct {
+		email     string
+		wantEmail string
+		wantCode  string
+	}{
+		"success: normalized_to_lower_case": {
+			email:     "Scalar.Target@Example.COM",
+			wantEmail: "scalar.target@example.com",
+		},
+		"validation_error: no_at_sign": {
+			email:    "invalid",
+			wantCode: "VALIDATION_ERROR",
+		},
+		"validation_error: single_label_domain": {
+			email:    "invalid@localhost",
+			wantCode: "VALIDATION_ERROR",
+		},
+		"validation_error: consecutive_dots": {
+			email:    "in..valid@example.com",
+			wantCode: "VALIDATION_ERROR",
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			mutation := api.NewQuery(fmt.Sprintf(`
+			mutation CreateUser {
+				createUser(input: {name: "%s", email: "%s"}) {
+					status
+					metadata {
+						user {
+							email
+						}
+					}
+				}
+			}
+			`, strings.ReplaceAll(tc, ": ", "_"), tt.email))
+			//// email is visible to administrators
+			header, err := api.BearerHeader(TokenSecret, "admin", auth.RoleAdmin)
+			if err != nil {
+				t.Fatalf("cause error when sign token. error = %v", err)
+			}
+			resBytes, err := api.PostGraphQLRequestWithHeader(mutation, Server, header)
+			if err != nil {
+				t.Fatalf("cause error when post graphql request. error = %v", err)
+			}
+
+			// then
+			if tt.wantCode != "" {
+				//// invalid values fail at parse time before the resolver
+				var errs graphQLErrorsResponse
+				if err := json.Unmarshal(resBytes, &errs); err != nil {
+					t.Fatalf("cause error when unmarshal response. error = %v", err)
+				}
+				if len(errs.Errors) == 0 || errs.Errors[0].Extensions.Code != tt.wantCode {
+					t.Errorf("unexpected errors: %s", resBytes)
+				}
+				return
+			}
+			var actual createUserMutationResponse
+			if err := json.Unmarshal(resBytes, &actual); err != nil {
+				t.Fatalf("cause error when unmarshal response. error = %v", err)
+			}
+			got := actual.Data.CreateUserOutput
+			if got.Metadata == nil || got.Metadata.User.Email == nil {
+				t.Fatalf("email is empty: %s", resBytes)
+			}
+			if diff := cmp.Diff(tt.wantEmail, *got.Metadata.User.Email); diff != "" {
+				t.Errorf("unexpected email: %v", diff)
+			}
+		})
+	}
+}
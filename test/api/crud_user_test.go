@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
 	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
 )
@@ -38,6 +39,7 @@ func TestCrudUser(t *testing.T) {
 			metadata {
 				user {
 					id
+					databaseId
 				}
 			}
 		}
@@ -51,7 +53,7 @@ func TestCrudUser(t *testing.T) {
 	}
 
 	/// then
-	var userID string
+	var userID, databaseID string
 	{
 		var actual createUserMutationResponse
 		err = json.Unmarshal(resBytes1, &actual)
@@ -60,9 +62,11 @@ func TestCrudUser(t *testing.T) {
 		}
 		// get userID for later test
 		userID = actual.Data.CreateUserOutput.Metadata.User.ID
+		databaseID = actual.Data.CreateUserOutput.Metadata.User.DatabaseID
 
 		// set fixed ID for comparison
 		actual.Data.CreateUserOutput.Metadata.User.ID = "1"
+		actual.Data.CreateUserOutput.Metadata.User.DatabaseID = "1"
 		expected := createUserMutationResponse{
 			Data: struct {
 				CreateUserOutput graph.CreateUserOutput `json:"createUser"`
@@ -71,7 +75,8 @@ func TestCrudUser(t *testing.T) {
 					Status: graph.MutationStatusSuccess,
 					Metadata: &graph.CreateUserOutputMetadata{
 						User: &graph.User{
-							ID: "1",
+							ID:         "1",
+							DatabaseID: "1",
 						},
 					},
 					ErrorMessage: nil,
@@ -95,17 +100,23 @@ func TestCrudUser(t *testing.T) {
 		}
 	}
 	`,
-		userID,
+		databaseID,
 	))
 
 	/// when
-	resBytes2, err := api.PostGraphQLRequest(userQuery, Server)
+	//// email is visible to the user themself
+	header, err := api.BearerHeader(TokenSecret, databaseID, auth.RoleUser)
+	if err != nil {
+		t.Errorf("cause error when sign token. error = %v", err)
+	}
+	resBytes2, err := api.PostGraphQLRequestWithHeader(userQuery, Server, header)
 	if err != nil {
 		t.Errorf("cause error when post graphql request. error = %v", err)
 	}
 
 	/// then
 	{
+		email := "test@example.com"
 		var actual userQueryResponse
 		err = json.Unmarshal(resBytes2, &actual)
 		if err != nil {
@@ -118,7 +129,7 @@ func TestCrudUser(t *testing.T) {
 				User: graph.User{
 					ID:    userID,
 					Name:  "test",
-					Email: "test@example.com",
+					Email: &email,
 				},
 			},
 		}
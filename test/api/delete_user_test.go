@@ -0,0 +1,190 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+type deleteUserMutationResponse struct {
+	Data struct {
+		DeleteUserOutput graph.DeleteUserOutput `json:"deleteUser"`
+	} `json:"data"`
+}
+
+type restoreUserMutationResponse struct {
+	Data struct {
+		RestoreUserOutput graph.RestoreUserOutput `json:"restoreUser"`
+	} `json:"data"`
+}
+
+type graphQLErrorsResponse struct {
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code string `json:"code"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func TestDeleteAndRestoreUser(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createUserMutation := api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "delete_target", email: "delete_target@example.com"}) {
+			metadata {
+				user {
+					id
+					databaseId
+				}
+			}
+		}
+	}
+	`)
+	resBytes, err := api.PostGraphQLRequest(createUserMutation, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var created createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &created); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	userID := created.Data.CreateUserOutput.Metadata.User.ID
+	databaseID := created.Data.CreateUserOutput.Metadata.User.DatabaseID
+	//// users can delete and restore themselves
+	ownerHeader, err := api.BearerHeader(TokenSecret, databaseID, auth.RoleUser)
+	if err != nil {
+		t.Fatalf("cause error when sign token. error = %v", err)
+	}
+
+	userQuery := api.NewQuery(fmt.Sprintf(`
+	query User {
+		user(id: "%s") {
+			id
+		}
+	}
+	`, databaseID))
+
+	// delete
+	/// when
+	deleteUserMutation := api.NewQuery(fmt.Sprintf(`
+	mutation DeleteUser {
+		deleteUser(input: {id: "%s"}) {
+			status
+		}
+	}
+	`, userID))
+	resBytes, err = api.PostGraphQLRequestWithHeader(deleteUserMutation, Server, ownerHeader)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	/// then
+	{
+		var actual deleteUserMutationResponse
+		if err := json.Unmarshal(resBytes, &actual); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if diff := cmp.Diff(graph.MutationStatusSuccess, actual.Data.DeleteUserOutput.Status); diff != "" {
+			t.Errorf("unexpected status: %v", diff)
+		}
+
+		// deleted user is excluded
+		resBytes, err = api.PostGraphQLRequest(userQuery, Server)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+		var errs graphQLErrorsResponse
+		if err := json.Unmarshal(resBytes, &errs); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if len(errs.Errors) == 0 || errs.Errors[0].Extensions.Code != "NOT_FOUND" {
+			t.Errorf("deleted user should not be found: %v", errs)
+		}
+
+		// administrators can read deleted user with includeDeleted
+		adminHeader, err := api.BearerHeader(TokenSecret, "admin", auth.RoleAdmin)
+		if err != nil {
+			t.Fatalf("cause error when sign token. error = %v", err)
+		}
+		includeDeletedQuery := api.NewQuery(fmt.Sprintf(`
+		query User {
+			user(id: "%s", includeDeleted: true) {
+				id
+			}
+		}
+		`, databaseID))
+		resBytes, err = api.PostGraphQLRequestWithHeader(includeDeletedQuery, Server, adminHeader)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+		var deleted userQueryResponse
+		if err := json.Unmarshal(resBytes, &deleted); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if diff := cmp.Diff(userID, deleted.Data.User.ID); diff != "" {
+			t.Errorf("deleted user should be found by administrators: %v", diff)
+		}
+
+		// deleting twice is not found
+		resBytes, err = api.PostGraphQLRequestWithHeader(deleteUserMutation, Server, ownerHeader)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+		var again deleteUserMutationResponse
+		if err := json.Unmarshal(resBytes, &again); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if diff := cmp.Diff(graph.MutationStatusNotFound, again.Data.DeleteUserOutput.Status); diff != "" {
+			t.Errorf("unexpected status: %v", diff)
+		}
+	}
+
+	// restore
+	/// when
+	restoreUserMutation := api.NewQuery(fmt.Sprintf(`
+	mutation RestoreUser {
+		restoreUser(input: {id: "%s"}) {
+			status
+		}
+	}
+	`, userID))
+	resBytes, err = api.PostGraphQLRequestWithHeader(restoreUserMutation, Server, ownerHeader)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	/// then
+	{
+		var actual restoreUserMutationResponse
+		if err := json.Unmarshal(resBytes, &actual); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if diff := cmp.Diff(graph.MutationStatusSuccess, actual.Data.RestoreUserOutput.Status); diff != "" {
+			t.Errorf("unexpected status: %v", diff)
+		}
+
+		// restored user is found again
+		resBytes, err = api.PostGraphQLRequest(userQuery, Server)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+		var found userQueryResponse
+		if err := json.Unmarshal(resBytes, &found); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if diff := cmp.Diff(userID, found.Data.User.ID); diff != "" {
+			t.Errorf("unexpected user: %v", diff)
+		}
+	}
+}
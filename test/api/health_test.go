@@ -0,0 +1,34 @@
+//go:build api
+
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHealth(t *testing.T) {
+
+	t.Parallel()
+
+	for _, path := range []string{"/health/live", "/health/ready"} {
+		path := path
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			req := httptest.NewRequest(echo.GET, path, nil)
+			rec := httptest.NewRecorder()
+			Server.ServeHTTP(rec, req)
+
+			// then
+			if diff := cmp.Diff(http.StatusOK, rec.Code); diff != "" {
+				t.Errorf("unexpected response code: %v, body: %s", diff, rec.Body.String())
+			}
+		})
+	}
+}
@@ -1,22 +1,66 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
+	"net/url"
 	"strings"
+
+	"github.com/rikeda71/go-gql-sqlc-template/internal/persisted"
 )
 
 type Query struct {
 	query string
+	// persisted APQのハッシュを送信する場合にtrue
+	persisted bool
+	// hashOnly クエリ本体を送信せずハッシュのみ送信する場合にtrue
+	hashOnly bool
 }
 
 func NewQuery(query string) Query {
 	return Query{query: query}
 }
 
+// NewPersistedQuery APQのハッシュを付与したクエリを作成する
+// hashOnly が true の場合はクエリ本体を送信しない
+func NewPersistedQuery(query string, hashOnly bool) Query {
+	return Query{query: query, persisted: true, hashOnly: hashOnly}
+}
+
 func (q Query) RequestBody() io.Reader {
-	return strings.NewReader(`{"query": "` + q.escaped() + `"}`)
+	if !q.persisted {
+		return strings.NewReader(`{"query": "` + q.escaped() + `"}`)
+	}
+
+	body := map[string]interface{}{
+		"extensions": map[string]interface{}{
+			"persistedQuery": map[string]interface{}{
+				"version":    1,
+				"sha256Hash": persisted.Hash(q.normalized()),
+			},
+		},
+	}
+	if !q.hashOnly {
+		body["query"] = q.normalized()
+	}
+	b, _ := json.Marshal(body)
+	return bytes.NewReader(b)
+}
+
+// URLValues GETリクエストのクエリパラメータを返す
+func (q Query) URLValues() url.Values {
+	return url.Values{"query": {q.normalized()}}
+}
+
+func (q Query) String() string {
+	return q.query
+}
+
+func (q Query) normalized() string {
+	return strings.Replace(strings.Replace(q.query, "\n", "", -1), "\t", " ", -1)
 }
 
 func (q Query) escaped() string {
-	return strings.Replace(strings.Replace(strings.Replace(q.query, "\n", "", -1), "\t", " ", -1), "\"", "\\\"", -1)
+	return strings.Replace(q.normalized(), "\"", "\\\"", -1)
 }
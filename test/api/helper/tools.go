@@ -10,15 +10,24 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/go-cmp/cmp"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
 )
 
 func PostGraphQLRequest(query Query, server *echo.Echo) ([]byte, error) {
+	return PostGraphQLRequestWithHeader(query, server, http.Header{})
+}
+
+// PostGraphQLRequestWithHeader ヘッダを付与してGraphQLリクエストを送信する
+func PostGraphQLRequestWithHeader(query Query, server *echo.Echo, header http.Header) ([]byte, error) {
 	req := httptest.NewRequest(echo.POST, "/graphql", query.RequestBody())
+	req.Header = header.Clone()
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 	rec := httptest.NewRecorder()
@@ -31,6 +40,36 @@ func PostGraphQLRequest(query Query, server *echo.Echo) ([]byte, error) {
 	return rec.Body.Bytes(), nil
 }
 
+// GetGraphQLRequestWithHeader ヘッダを付与してGETでGraphQLリクエストを送信する
+// キャッシュを検証するためステータスコードによらずレスポンスを返す
+func GetGraphQLRequestWithHeader(query Query, server *echo.Echo, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(echo.GET, "/graphql?"+query.URLValues().Encode(), nil)
+	req.Header = header.Clone()
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	fmt.Printf("query: %s\nresponse: %s", query, rec.Body.String())
+	return rec
+}
+
+// BearerHeader HS256で署名したトークンをAuthorizationヘッダに設定する
+func BearerHeader(secret string, userID string, role auth.Role) (http.Header, error) {
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Role: role,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	return header, nil
+}
+
 // ExecuteSQLsFromDir ディレクトリ内のSQLファイルを昇順にソートして実行する
 func ExecuteSQLsFromDir(dir string, conn *pgxpool.Pool, purpose string) error {
 	fmt.Println("===============================")
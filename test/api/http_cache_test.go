@@ -0,0 +1,107 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+func TestHTTPCache(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createUserMutation := api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "cache_target", email: "cache_target@example.com"}) {
+			metadata {
+				user {
+					databaseId
+				}
+			}
+		}
+	}
+	`)
+	resBytes, err := api.PostGraphQLRequest(createUserMutation, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var created createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &created); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	userQuery := api.NewQuery(fmt.Sprintf(`
+	query User {
+		user(id: "%s") {
+			id
+			name
+		}
+	}
+	`, created.Data.CreateUserOutput.Metadata.User.DatabaseID))
+
+	// cacheable query
+	/// when
+	rec := api.GetGraphQLRequestWithHeader(userQuery, Server, http.Header{})
+
+	/// then
+	if diff := cmp.Diff(http.StatusOK, rec.Code); diff != "" {
+		t.Fatalf("unexpected status: %v", diff)
+	}
+	if diff := cmp.Diff("public, max-age=60", rec.Header().Get("Cache-Control")); diff != "" {
+		t.Errorf("unexpected cache control: %v", diff)
+	}
+	//// shared caches must not serve the public response to authenticated requests
+	if diff := cmp.Diff("Authorization", rec.Header().Get("Vary")); diff != "" {
+		t.Errorf("unexpected vary: %v", diff)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("etag should be set")
+	}
+
+	// revalidation
+	/// when
+	header := http.Header{}
+	header.Set("If-None-Match", etag)
+	rec = api.GetGraphQLRequestWithHeader(userQuery, Server, header)
+
+	/// then
+	if diff := cmp.Diff(http.StatusNotModified, rec.Code); diff != "" {
+		t.Errorf("unexpected status: %v", diff)
+	}
+	if diff := cmp.Diff(0, rec.Body.Len()); diff != "" {
+		t.Errorf("body should be empty: %v", diff)
+	}
+	//// the status recorded by echo is used by request metrics
+	metricsRec := httptest.NewRecorder()
+	Server.ServeHTTP(metricsRec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !regexp.MustCompile(`api_requests_total\{code="304",[^}]*url="/graphql"\}`).MatchString(metricsRec.Body.String()) {
+		t.Errorf("304 should be recorded in request metrics")
+	}
+
+	// query without @cacheControl
+	/// when
+	rec = api.GetGraphQLRequestWithHeader(api.NewQuery(`query Users { users(first: 1) { pageInfo { hasNextPage } } }`), Server, http.Header{})
+
+	/// then
+	if diff := cmp.Diff("no-store", rec.Header().Get("Cache-Control")); diff != "" {
+		t.Errorf("unexpected cache control: %v", diff)
+	}
+
+	// mutation over GET
+	/// when
+	rec = api.GetGraphQLRequestWithHeader(createUserMutation, Server, http.Header{})
+
+	/// then
+	if diff := cmp.Diff(http.StatusNotAcceptable, rec.Code); diff != "" {
+		t.Errorf("mutation should be rejected: %v", diff)
+	}
+}
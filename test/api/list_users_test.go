@@ -0,0 +1,150 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+type usersQueryResponse struct {
+	Data struct {
+		Users graph.UserConnection `json:"users"`
+	} `json:"data"`
+}
+
+func TestListUsers(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createdIDs := make([]string, 0, 3)
+	for i := range 3 {
+		mutation := api.NewQuery(fmt.Sprintf(`
+		mutation CreateUser {
+			createUser(input: {name: "list_%d", email: "list_%d@example.com"}) {
+				metadata {
+					user {
+						id
+					}
+				}
+			}
+		}
+		`, i, i))
+		resBytes, err := api.PostGraphQLRequest(mutation, Server)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+		var res createUserMutationResponse
+		if err := json.Unmarshal(resBytes, &res); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		createdIDs = append(createdIDs, res.Data.CreateUserOutput.Metadata.User.ID)
+	}
+
+	testCases := map[string]struct {
+		direction graph.OrderDirection
+		pageArg   string
+		cursorArg string
+		nextPage  func(info *graph.PageInfo) (*string, bool)
+	}{
+		"forward: ascending": {
+			direction: graph.OrderDirectionAsc,
+			pageArg:   "first",
+			cursorArg: "after",
+			nextPage: func(info *graph.PageInfo) (*string, bool) {
+				return info.EndCursor, info.HasNextPage
+			},
+		},
+		"backward: descending": {
+			direction: graph.OrderDirectionDesc,
+			pageArg:   "last",
+			cursorArg: "before",
+			nextPage: func(info *graph.PageInfo) (*string, bool) {
+				return info.StartCursor, info.HasPreviousPage
+			},
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			// when
+			/// walk through all pages
+			var gotIDs []string
+			var cursor *string
+			for {
+				cursorArg := ""
+				if cursor != nil {
+					cursorArg = fmt.Sprintf(`, %s: "%s"`, tt.cursorArg, *cursor)
+				}
+				query := api.NewQuery(fmt.Sprintf(`
+				query Users {
+					users(%s: 2%s, orderBy: {field: ID, direction: %s}) {
+						edges {
+							node {
+								id
+							}
+						}
+						pageInfo {
+							hasPreviousPage
+							hasNextPage
+							startCursor
+							endCursor
+						}
+					}
+				}
+				`, tt.pageArg, cursorArg, tt.direction))
+				resBytes, err := api.PostGraphQLRequest(query, Server)
+				if err != nil {
+					t.Fatalf("cause error when post graphql request. error = %v", err)
+				}
+				var res usersQueryResponse
+				if err := json.Unmarshal(resBytes, &res); err != nil {
+					t.Fatalf("cause error when unmarshal response. error = %v", err)
+				}
+
+				pageIDs := make([]string, 0, len(res.Data.Users.Edges))
+				for _, e := range res.Data.Users.Edges {
+					pageIDs = append(pageIDs, e.Node.ID)
+				}
+				if tt.pageArg == "last" {
+					// pages are walked from the end
+					gotIDs = append(pageIDs, gotIDs...)
+				} else {
+					gotIDs = append(gotIDs, pageIDs...)
+				}
+
+				next, ok := tt.nextPage(res.Data.Users.PageInfo)
+				if !ok {
+					break
+				}
+				cursor = next
+			}
+
+			// then
+			/// created users are listed in the requested order
+			positions := make([]int, 0, len(createdIDs))
+			for _, id := range createdIDs {
+				pos := slices.Index(gotIDs, id)
+				if pos < 0 {
+					t.Fatalf("user %s is not listed", id)
+				}
+				positions = append(positions, pos)
+			}
+			sorted := slices.IsSorted(positions)
+			if tt.direction == graph.OrderDirectionDesc {
+				slices.Reverse(positions)
+				sorted = slices.IsSorted(positions)
+			}
+			if !sorted {
+				t.Errorf("users are not listed in %s order: %v", tt.direction, positions)
+			}
+		})
+	}
+}
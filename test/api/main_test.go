@@ -18,12 +18,21 @@ import (
 	"github.com/ory/dockertest"
 	"github.com/ory/dockertest/docker"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rikeda71/go-gql-sqlc-template/internal"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/event"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/db"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/health"
 	"github.com/rikeda71/go-gql-sqlc-template/internal/metrics"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/persisted"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/requestid"
 	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
 )
 
+// TokenSecret is a secret to sign tokens in tests
+const TokenSecret = "test-secret"
+
 var (
 	sqlcClient *db.Queries
 	Pool       *pgxpool.Pool
@@ -98,7 +107,8 @@ func TestMain(m *testing.M) {
 		}
 
 		// setup global sqlc client
-		sqlcClient = db.New(p)
+		// SQL is tagged with request IDs to run queries in the same way as SQL_REQUEST_ID_COMMENT=true
+		sqlcClient = db.New(requestid.NewDBTX(p))
 		Pool = p
 		return nil
 	}); err != nil {
@@ -121,14 +131,31 @@ func TestMain(m *testing.M) {
 	}
 
 	// setup app
+	/// setup events
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	broker := event.NewBroker(Pool, sqlcClient)
+	go broker.Run(brokerCtx)
+	/// tokens are signed with TokenSecret in tests
+	cnf.JWTSecret = TokenSecret
+	verifier, err := auth.NewVerifier(cnf.VerifierConfig())
+	if err != nil {
+		log.Fatalf("could not create verifier: %v", err)
+	}
 	/// setup graphql handler
-	gqlHandler, err := internal.NewGraphQLHandler(cnf, sqlcClient, metrics.NewClient())
+	//// persisted queries are stored in the database to test the table
+	cnf.PersistedQueryCache = persisted.CachePostgres
+	//// metrics are isolated from the global registry
+	registry := prometheus.NewRegistry()
+	metricsClient := metrics.NewClientWithRegistry(registry, registry)
+	gqlHandler, err := internal.NewGraphQLHandler(cnf, sqlcClient, broker, verifier, metricsClient)
 	if err != nil {
 		log.Fatalf("could not create graphql handler: %v", err)
 	}
-	s := internal.NewServer(cnf.Port, *gqlHandler)
+	/// migrations are applied without dbmate in tests, so the migration version is not checked
+	healthHandler := health.NewHandler(health.NewPingChecker(Pool))
+	s := internal.NewServer(cnf.Port, *gqlHandler, healthHandler, verifier, metricsClient)
 	go func() {
-		_ = s.Start(false)
+		_ = s.Start(internal.PlaygroundConfig{Kind: internal.PlaygroundNone})
 	}()
 	if err != nil {
 		log.Fatalf("could not start server: %v", err)
@@ -136,6 +163,7 @@ func TestMain(m *testing.M) {
 	Server = s.Server()
 
 	code := m.Run()
+	stopBroker()
 
 	if err := pool.Purge(resource); err != nil {
 		log.Fatalf("could not purge resource: %v", err)
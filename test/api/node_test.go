@@ -0,0 +1,167 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/globalid"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+type nodesQueryResponse struct {
+	Data struct {
+		Node  *graph.User   `json:"node"`
+		Nodes []*graph.User `json:"nodes"`
+	} `json:"data"`
+}
+
+func TestNode(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createUserMutation := api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "node_target", email: "node_target@example.com"}) {
+			metadata {
+				user {
+					id
+					databaseId
+				}
+			}
+		}
+	}
+	`)
+	resBytes, err := api.PostGraphQLRequest(createUserMutation, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var created createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &created); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	user := created.Data.CreateUserOutput.Metadata.User
+	if diff := cmp.Diff(globalid.Encode("User", user.DatabaseID), user.ID); diff != "" {
+		t.Errorf("unexpected global id: %v", diff)
+	}
+	unknownID := globalid.Encode("User", "00000000-0000-0000-0000-000000000000")
+
+	// when
+	nodesQuery := api.NewQuery(fmt.Sprintf(`
+	query Nodes {
+		node(id: "%s") {
+			id
+			... on User {
+				name
+			}
+		}
+		nodes(ids: ["%s", "%s"]) {
+			id
+			... on User {
+				name
+			}
+		}
+	}
+	`, user.ID, user.ID, unknownID))
+	resBytes, err = api.PostGraphQLRequest(nodesQuery, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	// then
+	var actual nodesQueryResponse
+	if err := json.Unmarshal(resBytes, &actual); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	want := &graph.User{ID: user.ID, Name: "node_target"}
+	if diff := cmp.Diff(want, actual.Data.Node); diff != "" {
+		t.Errorf("unexpected node: %v", diff)
+	}
+	if diff := cmp.Diff([]*graph.User{want, nil}, actual.Data.Nodes); diff != "" {
+		t.Errorf("unexpected nodes: %v", diff)
+	}
+}
+
+func TestNodesWithInvalidIDs(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createUserMutation := api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "nodes_partial", email: "nodes_partial@example.com"}) {
+			metadata {
+				user {
+					id
+				}
+			}
+		}
+	}
+	`)
+	resBytes, err := api.PostGraphQLRequest(createUserMutation, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var created createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &created); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	user := created.Data.CreateUserOutput.Metadata.User
+	unknownTypeID := globalid.Encode("Unknown", "1")
+
+	// when
+	nodesQuery := api.NewQuery(fmt.Sprintf(`
+	query Nodes {
+		nodes(ids: ["malformed", "%s", "%s"]) {
+			id
+			... on User {
+				name
+			}
+		}
+	}
+	`, user.ID, unknownTypeID))
+	resBytes, err = api.PostGraphQLRequest(nodesQuery, Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	// then
+	var actual struct {
+		nodesQueryResponse
+		Errors []struct {
+			Path       []interface{} `json:"path"`
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(resBytes, &actual); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	// valid IDs are resolved even if other IDs are invalid
+	want := []*graph.User{nil, {ID: user.ID, Name: "nodes_partial"}, nil}
+	if diff := cmp.Diff(want, actual.Data.Nodes); diff != "" {
+		t.Errorf("unexpected nodes: %v", diff)
+	}
+	// errors are reported at the index of the invalid IDs
+	type nodeError struct {
+		Path []interface{}
+		Code string
+	}
+	var gotErrors []nodeError
+	for _, e := range actual.Errors {
+		gotErrors = append(gotErrors, nodeError{Path: e.Path, Code: e.Extensions.Code})
+	}
+	wantErrors := []nodeError{
+		{Path: []interface{}{"nodes", float64(0)}, Code: "VALIDATION_ERROR"},
+		{Path: []interface{}{"nodes", float64(2)}, Code: "VALIDATION_ERROR"},
+	}
+	if diff := cmp.Diff(wantErrors, gotErrors); diff != "" {
+		t.Errorf("unexpected errors: %v", diff)
+	}
+}
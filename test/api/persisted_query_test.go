@@ -0,0 +1,87 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/persisted"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+func TestAutomaticPersistedQuery(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	query := `
+	query PersistedUsers {
+		users(first: 1) {
+			pageInfo {
+				hasNextPage
+			}
+		}
+	}
+	`
+	header, err := api.BearerHeader(TokenSecret, "admin", auth.RoleAdmin)
+	if err != nil {
+		t.Fatalf("cause error when sign token. error = %v", err)
+	}
+
+	// unknown hash
+	/// when
+	resBytes, err := api.PostGraphQLRequestWithHeader(api.NewPersistedQuery(query, true), Server, header)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	/// then
+	{
+		var errs graphQLErrorsResponse
+		if err := json.Unmarshal(resBytes, &errs); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if len(errs.Errors) == 0 || errs.Errors[0].Extensions.Code != persisted.CodeNotFound {
+			t.Errorf("unknown hash should not be found: %v", errs)
+		}
+	}
+
+	// register with the query
+	/// when
+	resBytes, err = api.PostGraphQLRequestWithHeader(api.NewPersistedQuery(query, false), Server, header)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	/// then
+	{
+		var errs graphQLErrorsResponse
+		if err := json.Unmarshal(resBytes, &errs); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if diff := cmp.Diff(0, len(errs.Errors)); diff != "" {
+			t.Errorf("unexpected errors: %v", diff)
+		}
+	}
+
+	// execute with the hash only
+	/// when
+	resBytes, err = api.PostGraphQLRequestWithHeader(api.NewPersistedQuery(query, true), Server, header)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	/// then
+	{
+		var errs graphQLErrorsResponse
+		if err := json.Unmarshal(resBytes, &errs); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		if diff := cmp.Diff(0, len(errs.Errors)); diff != "" {
+			t.Errorf("query should be found by the hash: %v", diff)
+		}
+	}
+}
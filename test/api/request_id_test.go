@@ -0,0 +1,54 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/requestid"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+func TestRequestID(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	/// users is not cacheable, so the request ID is returned in extensions
+	usersQuery := api.NewQuery(`
+	query Users {
+		users(first: 1) {
+			edges {
+				node {
+					id
+				}
+			}
+		}
+	}
+	`)
+	header := http.Header{}
+	header.Set(requestid.Header, "request-id-test")
+
+	// when
+	rec := api.GetGraphQLRequestWithHeader(usersQuery, Server, header)
+
+	// then
+	if diff := cmp.Diff(http.StatusOK, rec.Code); diff != "" {
+		t.Fatalf("unexpected status: %v", diff)
+	}
+	if diff := cmp.Diff("request-id-test", rec.Header().Get(requestid.Header)); diff != "" {
+		t.Errorf("unexpected request id header: %v", diff)
+	}
+	var actual struct {
+		Extensions map[string]interface{} `json:"extensions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &actual); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	if diff := cmp.Diff("request-id-test", actual.Extensions[requestid.ExtensionKey]); diff != "" {
+		t.Errorf("unexpected request id extension: %v", diff)
+	}
+}
@@ -0,0 +1,166 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type userSubscriptionPayload struct {
+	Data map[string]graph.User `json:"data"`
+}
+
+// subscribe starts a subscription with graphql-transport-ws protocol
+func subscribe(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/graphql", nil)
+	if err != nil {
+		t.Fatalf("cause error when dial websocket. error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	header, err := api.BearerHeader(TokenSecret, "admin", auth.RoleAdmin)
+	if err != nil {
+		t.Fatalf("cause error when sign token. error = %v", err)
+	}
+	initPayload, _ := json.Marshal(map[string]string{"Authorization": header.Get("Authorization")})
+	if err := conn.WriteJSON(wsMessage{Type: "connection_init", Payload: initPayload}); err != nil {
+		t.Fatalf("cause error when init connection. error = %v", err)
+	}
+	var ack wsMessage
+	if err := conn.ReadJSON(&ack); err != nil || ack.Type != "connection_ack" {
+		t.Fatalf("connection is not acknowledged. message = %v, error = %v", ack, err)
+	}
+
+	subscribePayload, _ := json.Marshal(map[string]string{"query": query})
+	if err := conn.WriteJSON(wsMessage{ID: "1", Type: "subscribe", Payload: subscribePayload}); err != nil {
+		t.Fatalf("cause error when subscribe. error = %v", err)
+	}
+	// wait for the subscription to start listening events
+	time.Sleep(500 * time.Millisecond)
+	return conn
+}
+
+// receiveUser waits for an event of the user with the name
+// events of other tests are skipped
+func receiveUser(t *testing.T, conn *websocket.Conn, field string, name string) *graph.User {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("cause error when receive event. error = %v", err)
+		}
+		if msg.Type != "next" {
+			continue
+		}
+		var payload userSubscriptionPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			t.Fatalf("cause error when unmarshal event. error = %v", err)
+		}
+		if u := payload.Data[field]; u.Name == name {
+			return &u
+		}
+	}
+}
+
+func TestSubscription(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	server := httptest.NewServer(Server)
+	defer server.Close()
+
+	// userCreated
+	/// when
+	created := subscribe(t, server, `subscription { userCreated { id name } }`)
+	resBytes, err := api.PostGraphQLRequest(api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "subscription_target", email: "subscription_target@example.com"}) {
+			metadata {
+				user {
+					id
+				}
+			}
+		}
+	}
+	`), Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+	var res createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &res); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	userID := res.Data.CreateUserOutput.Metadata.User.ID
+
+	/// then
+	got := receiveUser(t, created, "userCreated", "subscription_target")
+	if diff := cmp.Diff(userID, got.ID); diff != "" {
+		t.Errorf("unexpected user: %v", diff)
+	}
+
+	// userUpdated
+	/// when
+	updated := subscribe(t, server, fmt.Sprintf(`subscription { userUpdated(id: "%s") { id name } }`, userID))
+	adminHeader, err := api.BearerHeader(TokenSecret, "admin", auth.RoleAdmin)
+	if err != nil {
+		t.Fatalf("cause error when sign token. error = %v", err)
+	}
+	_, err = api.PostGraphQLRequestWithHeader(api.NewQuery(fmt.Sprintf(`
+	mutation UpdateUser {
+		updateUser(input: {id: "%s", name: "subscription_updated"}) {
+			status
+		}
+	}
+	`, userID)), Server, adminHeader)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	/// then
+	got = receiveUser(t, updated, "userUpdated", "subscription_updated")
+	if diff := cmp.Diff(userID, got.ID); diff != "" {
+		t.Errorf("unexpected user: %v", diff)
+	}
+
+	// userUpdated by soft-delete and restore
+	for _, mutation := range []string{"deleteUser", "restoreUser"} {
+		/// when
+		_, err = api.PostGraphQLRequestWithHeader(api.NewQuery(fmt.Sprintf(`
+		mutation {
+			%s(input: {id: "%s"}) {
+				status
+			}
+		}
+		`, mutation, userID)), Server, adminHeader)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+
+		/// then
+		got = receiveUser(t, updated, "userUpdated", "subscription_updated")
+		if diff := cmp.Diff(userID, got.ID); diff != "" {
+			t.Errorf("unexpected user of %s: %v", mutation, diff)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+func TestUserTimestamps(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	before := time.Now().Add(-time.Minute)
+
+	// create
+	/// when
+	resBytes, err := api.PostGraphQLRequest(api.NewQuery(`
+	mutation CreateUser {
+		createUser(input: {name: "timestamps_target", email: "timestamps_target@example.com"}) {
+			metadata {
+				user {
+					id
+					createdAt
+					updatedAt
+				}
+			}
+		}
+	}
+	`), Server)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	/// then
+	var created createUserMutationResponse
+	if err := json.Unmarshal(resBytes, &created); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	user := created.Data.CreateUserOutput.Metadata.User
+	if user.CreatedAt.Before(before) || user.CreatedAt.Location() != time.UTC {
+		t.Errorf("unexpected createdAt: %v", user.CreatedAt)
+	}
+	if !user.UpdatedAt.Equal(user.CreatedAt) {
+		t.Errorf("updatedAt should be equal to createdAt: %v, %v", user.UpdatedAt, user.CreatedAt)
+	}
+
+	// update
+	/// when
+	header, err := api.BearerHeader(TokenSecret, "admin", auth.RoleAdmin)
+	if err != nil {
+		t.Fatalf("cause error when sign token. error = %v", err)
+	}
+	resBytes, err = api.PostGraphQLRequestWithHeader(api.NewQuery(fmt.Sprintf(`
+	mutation UpdateUser {
+		updateUser(input: {id: "%s", name: "timestamps_updated"}) {
+			status
+			metadata {
+				user {
+					createdAt
+					updatedAt
+				}
+			}
+		}
+	}
+	`, user.ID)), Server, header)
+	if err != nil {
+		t.Fatalf("cause error when post graphql request. error = %v", err)
+	}
+
+	/// then
+	var updated updateUserMutationResponse
+	if err := json.Unmarshal(resBytes, &updated); err != nil {
+		t.Fatalf("cause error when unmarshal response. error = %v", err)
+	}
+	got := updated.Data.UpdateUserOutput.Metadata.User
+	if !got.CreatedAt.Equal(user.CreatedAt) {
+		t.Errorf("createdAt should not be changed: %v, %v", got.CreatedAt, user.CreatedAt)
+	}
+	if !got.UpdatedAt.After(user.UpdatedAt) {
+		t.Errorf("updatedAt should be changed: %v, %v", got.UpdatedAt, user.UpdatedAt)
+	}
+}
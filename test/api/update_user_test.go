@@ -0,0 +1,129 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/auth"
+	"github.com/rikeda71/go-gql-sqlc-template/internal/generated/graph"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+type updateUserMutationResponse struct {
+	Data struct {
+		UpdateUserOutput graph.UpdateUserOutput `json:"updateUser"`
+	} `json:"data"`
+}
+
+func TestUpdateUser(t *testing.T) {
+
+	t.Parallel()
+
+	// given
+	createUser := func(name, email string) string {
+		mutation := api.NewQuery(fmt.Sprintf(`
+		mutation CreateUser {
+			createUser(input: {name: "%s", email: "%s"}) {
+				metadata {
+					user {
+						id
+					}
+				}
+			}
+		}
+		`, name, email))
+		resBytes, err := api.PostGraphQLRequest(mutation, Server)
+		if err != nil {
+			t.Fatalf("cause error when post graphql request. error = %v", err)
+		}
+		var res createUserMutationResponse
+		if err := json.Unmarshal(resBytes, &res); err != nil {
+			t.Fatalf("cause error when unmarshal response. error = %v", err)
+		}
+		return res.Data.CreateUserOutput.Metadata.User.ID
+	}
+	userID := createUser("update_target", "update_target@example.com")
+	updatedEmail := "updated@example.com"
+	_ = createUser("update_other", "update_other@example.com")
+
+	testCases := map[string]struct {
+		input      string
+		wantStatus graph.MutationStatus
+		wantUser   *graph.User
+	}{
+		"success: update_email_only": {
+			input:      fmt.Sprintf(`{id: "%s", email: "updated@example.com"}`, userID),
+			wantStatus: graph.MutationStatusSuccess,
+			wantUser: &graph.User{
+				ID:    userID,
+				Name:  "update_target",
+				Email: &updatedEmail,
+			},
+		},
+		"already_exists: name": {
+			input:      fmt.Sprintf(`{id: "%s", name: "update_other"}`, userID),
+			wantStatus: graph.MutationStatusAlreadyExists,
+		},
+		"not_found: unknown_id": {
+			input:      `{id: "00000000-0000-0000-0000-000000000000", name: "unknown"}`,
+			wantStatus: graph.MutationStatusNotFound,
+		},
+		"validation_error: no_fields": {
+			input:      fmt.Sprintf(`{id: "%s"}`, userID),
+			wantStatus: graph.MutationStatusValidationError,
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			// when
+			mutation := api.NewQuery(fmt.Sprintf(`
+			mutation UpdateUser {
+				updateUser(input: %s) {
+					status
+					metadata {
+						user {
+							id
+							name
+							email
+						}
+					}
+				}
+			}
+			`, tt.input))
+			//// email is visible to administrators
+			header, err := api.BearerHeader(TokenSecret, "admin", auth.RoleAdmin)
+			if err != nil {
+				t.Fatalf("cause error when sign token. error = %v", err)
+			}
+			resBytes, err := api.PostGraphQLRequestWithHeader(mutation, Server, header)
+			if err != nil {
+				t.Fatalf("cause error when post graphql request. error = %v", err)
+			}
+
+			// then
+			var actual updateUserMutationResponse
+			if err := json.Unmarshal(resBytes, &actual); err != nil {
+				t.Fatalf("cause error when unmarshal response. error = %v", err)
+			}
+			got := actual.Data.UpdateUserOutput
+			if diff := cmp.Diff(tt.wantStatus, got.Status); diff != "" {
+				t.Errorf("unexpected status: %v", diff)
+			}
+			if tt.wantUser == nil {
+				return
+			}
+			if got.Metadata == nil {
+				t.Fatalf("metadata is empty")
+			}
+			if diff := cmp.Diff(tt.wantUser, got.Metadata.User); diff != "" {
+				t.Errorf("unexpected user: %v", diff)
+			}
+		})
+	}
+}
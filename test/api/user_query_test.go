@@ -0,0 +1,96 @@
+//go:build api
+
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	api "github.com/rikeda71/go-gql-sqlc-template/test/api/helper"
+)
+
+func TestUserQueryErrors(t *testing.T) {
+
+	t.Parallel()
+
+	testCases := map[string]struct {
+		query    string
+		wantCode string
+	}{
+		"not_found: unknown_id": {
+			query: `
+			query User {
+				user(id: "01890a5d-ac96-774b-bcce-b302099a8057") {
+					id
+				}
+			}
+			`,
+			wantCode: "NOT_FOUND",
+		},
+		"validation_error: invalid_id": {
+			query: `
+			query User {
+				user(id: "invalid") {
+					id
+				}
+			}
+			`,
+			wantCode: "VALIDATION_ERROR",
+		},
+		"validation_error: not_uuid_v7": {
+			query: `
+			query User {
+				user(id: "00000000-0000-0000-0000-000000000000") {
+					id
+				}
+			}
+			`,
+			wantCode: "VALIDATION_ERROR",
+		},
+		"forbidden: include_deleted_without_admin": {
+			query: `
+			query User {
+				user(id: "01890a5d-ac96-774b-bcce-b302099a8057", includeDeleted: true) {
+					id
+				}
+			}
+			`,
+			wantCode: "FORBIDDEN",
+		},
+	}
+
+	for tc, tt := range testCases {
+		tt := tt
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			resBytes, err := api.PostGraphQLRequest(api.NewQuery(tt.query), Server)
+			if err != nil {
+				t.Fatalf("cause error when post graphql request. error = %v", err)
+			}
+
+			// then
+			/// user is null and other fields are kept
+			var actual struct {
+				graphQLErrorsResponse
+				Data *struct {
+					User *struct{} `json:"user"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(resBytes, &actual); err != nil {
+				t.Fatalf("cause error when unmarshal response. error = %v", err)
+			}
+			if actual.Data == nil || actual.Data.User != nil {
+				t.Errorf("data.user should be null: %s", resBytes)
+			}
+			if len(actual.Errors) != 1 {
+				t.Fatalf("unexpected errors: %s", resBytes)
+			}
+			if diff := cmp.Diff(tt.wantCode, actual.Errors[0].Extensions.Code); diff != "" {
+				t.Errorf("unexpected code: %v", diff)
+			}
+		})
+	}
+}